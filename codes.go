@@ -2,16 +2,22 @@
 //
 // Intent:
 //   - Provide a small set of widely useful, human-readable codes.
-//   - Keep semantics open-ended: no HTTP/status/retry policy in core.
-//   - Allow projects to extend with their own codes without a central registry.
+//   - Keep semantics open-ended: codes are plain strings.
+//   - Allow projects to extend with their own codes; describing them in a
+//     Registry (registry.go) is optional.
 //
 // Conventions (documented, not enforced here):
-//   - Codes are lowercase snake_case ASCII.
+//   - Codes are lowercase snake_case ASCII segments.
+//   - Dots form a hierarchy: "unavailable.db.primary" IS-A "unavailable.db",
+//     which IS-A "unavailable". Predicates (HasCode, IsRetryable, ...) match
+//     ancestors, so precise codes do not defeat coarse policies.
 //   - Avoid the empty string for custom codes; it is never a built-in.
-//   - Higher-level modules (e.g., xgx-error-http, xgx-error-retry) may interpret codes;
-//     core does not attach policy or retry semantics.
+//   - Higher-level modules (e.g., xgx-error-http, xgx-error-retry) interpret codes
+//     through Registry metadata rather than hard-coded switches.
 package xgxerror
 
+import "strings"
+
 // NOTE: Code type is declared in error.go. Invariants are documented there.
 
 // Domain / validation
@@ -94,3 +100,73 @@ func (c Code) IsBuiltin() bool {
 	_, ok := builtinCodeSet[c]
 	return ok
 }
+
+// codeSep separates hierarchy levels in a namespaced code.
+const codeSep = "."
+
+// Parent returns the code one level up the dotted hierarchy, or "" for a
+// top-level code.
+//
+// Example:
+//
+//	Code("unavailable.db.primary").Parent() // "unavailable.db"
+//	CodeUnavailable.Parent()                // ""
+func (c Code) Parent() Code {
+	i := strings.LastIndex(string(c), codeSep)
+	if i < 0 {
+		return ""
+	}
+	return c[:i]
+}
+
+// IsA reports whether c equals ancestor or descends from it in the dotted
+// hierarchy. Matching is per segment: "not_found.user" IS-A "not_found", but
+// "not_found_user" is not.
+func (c Code) IsA(ancestor Code) bool {
+	if c == ancestor {
+		return true
+	}
+	if ancestor == "" {
+		return false
+	}
+	return strings.HasPrefix(string(c), string(ancestor)+codeSep)
+}
+
+// -----------------------------------------------------------------------------
+// Code sentinels (errors.Is interop)
+// -----------------------------------------------------------------------------
+
+// CodeErr is a comparable sentinel error standing for a Code. Native errors
+// report errors.Is(err, sentinel) when their code IS-A the sentinel's code,
+// so packages can export classic sentinels that match semantic constructors:
+//
+//	var ErrNotFound = xgxerror.CodeNotFound.Sentinel()
+//
+//	errors.Is(xgxerror.NotFound("user", 42), ErrNotFound) // true
+//
+// A CodeErr may also be returned directly; it carries its code via CodeVal,
+// which CodeOf, HasCode and DominantCode read like any native code.
+type CodeErr struct {
+	code Code
+}
+
+// Sentinel returns the sentinel error for c. Sentinels for the same code are
+// equal (==), so they can be created on demand.
+func (c Code) Sentinel() CodeErr { return CodeErr{code: c} }
+
+// Error returns the code string.
+func (s CodeErr) Error() string { return string(s.code) }
+
+// CodeVal returns the sentinel's code.
+func (s CodeErr) CodeVal() Code { return s.code }
+
+// Is reports whether target is a sentinel for an ancestor of (or the same)
+// code, so a "not_found.user" sentinel also IS a "not_found" sentinel.
+func (s CodeErr) Is(target error) bool { return matchesCodeSentinel(s.code, target) }
+
+// matchesCodeSentinel reports whether target is a non-empty code sentinel
+// that c IS-A.
+func matchesCodeSentinel(c Code, target error) bool {
+	t, ok := target.(CodeErr)
+	return ok && t.code != "" && c.IsA(t.code)
+}
@@ -2,6 +2,8 @@
 package xgxerror
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -127,3 +129,117 @@ func TestCode_StringUnderlyingValue(t *testing.T) {
 		}
 	}
 }
+
+func TestCode_Parent(t *testing.T) {
+	t.Parallel()
+
+	cases := map[Code]Code{
+		"unavailable.db.primary": "unavailable.db",
+		"unavailable.db":         CodeUnavailable,
+		CodeUnavailable:          "",
+		"":                       "",
+	}
+	for c, want := range cases {
+		if got := c.Parent(); got != want {
+			t.Fatalf("Code(%q).Parent() = %q, want %q", c, got, want)
+		}
+	}
+}
+
+func TestCode_IsA(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		c, ancestor Code
+		want        bool
+	}{
+		{"not_found.user", CodeNotFound, true},
+		{"unavailable.db.primary", CodeUnavailable, true},
+		{"unavailable.db.primary", "unavailable.db", true},
+		{CodeNotFound, CodeNotFound, true},
+		{CodeNotFound, "not_found.user", false},  // ancestor is more specific
+		{"not_found_user", CodeNotFound, false},  // no segment boundary
+		{"not_foundx.user", CodeNotFound, false}, // prefix but different segment
+		{CodeNotFound, "", false},
+		{"", "", true},
+	}
+	for _, tc := range cases {
+		if got := tc.c.IsA(tc.ancestor); got != tc.want {
+			t.Fatalf("Code(%q).IsA(%q) = %v, want %v", tc.c, tc.ancestor, got, tc.want)
+		}
+	}
+}
+
+func TestCodeSentinel_MatchesNativeErrorsViaErrorsIs(t *testing.T) {
+	t.Parallel()
+
+	errNotFound := CodeNotFound.Sentinel()
+	errUserNotFound := Code("not_found.user").Sentinel()
+
+	if !errors.Is(NotFound("user", 42), errNotFound) {
+		t.Fatalf("NotFound should match the not_found sentinel")
+	}
+	if !errors.Is(Recode(nil, "not_found.user"), errNotFound) || !errors.Is(Recode(nil, "not_found.user"), errUserNotFound) {
+		t.Fatalf("descendant codes should match ancestor sentinels")
+	}
+	if errors.Is(NotFound("user", 42), errUserNotFound) {
+		t.Fatalf("a coarser code must not match a more specific sentinel")
+	}
+	if errors.Is(Conflict("dup"), errNotFound) {
+		t.Fatalf("different codes must not match")
+	}
+
+	deep := fmt.Errorf("svc: %w", Join(Invalid("email", "bad"), Wrap(NotFound("order", 1), "load")))
+	if !errors.Is(deep, errNotFound) {
+		t.Fatalf("sentinels should match anywhere in the graph")
+	}
+	if !errors.Is(Defect(errors.New("bug")), CodeDefect.Sentinel()) || !errors.Is(Interrupt("x"), CodeInterrupt.Sentinel()) {
+		t.Fatalf("defects and interrupts match their fixed codes")
+	}
+	if !errors.Is(Interrupt("x"), context.Canceled) {
+		t.Fatalf("Is must not hide the unwrap chain")
+	}
+}
+
+func TestCodeSentinel_ValueSemantics(t *testing.T) {
+	t.Parallel()
+
+	if CodeNotFound.Sentinel() != CodeNotFound.Sentinel() {
+		t.Fatalf("sentinels for one code should be equal")
+	}
+	s := Code("not_found.user").Sentinel()
+	if s.Error() != "not_found.user" || s.CodeVal() != "not_found.user" {
+		t.Fatalf("Error/CodeVal: %q %q", s.Error(), s.CodeVal())
+	}
+	if !errors.Is(s, CodeNotFound.Sentinel()) || errors.Is(CodeNotFound.Sentinel(), s) {
+		t.Fatalf("sentinel-to-sentinel matching follows the hierarchy")
+	}
+	if errors.Is(Recode(nil, ""), Code("").Sentinel()) {
+		t.Fatalf("the empty code sentinel matches nothing")
+	}
+	if got := DominantCode(fmt.Errorf("x: %w", s), nil); got != "not_found.user" {
+		t.Fatalf("returned sentinels still carry their code: %q", got)
+	}
+	if CodeOf(CodeNotFound.Sentinel()) != CodeNotFound || CodeOf(fmt.Errorf("x: %w", s)) != "not_found.user" {
+		t.Fatalf("CodeOf must read returned sentinels")
+	}
+	if !HasCode(fmt.Errorf("x: %w", s), CodeNotFound) || HasCode(s, CodeConflict) {
+		t.Fatalf("HasCode must read returned sentinels")
+	}
+	if !IsDefect(fmt.Errorf("x: %w", CodeDefect.Sentinel())) {
+		t.Fatalf("IsDefect must read returned sentinels")
+	}
+}
+
+func TestCodeSentinel_DefectAndInterruptMatchOnlyTheirOwnCode(t *testing.T) {
+	t.Parallel()
+
+	d := Defect(errors.New("bug"))
+	if !errors.Is(d, CodeDefect.Sentinel()) || errors.Is(d, Code("defect.invariant").Sentinel()) {
+		t.Fatalf("defect should match only the defect sentinel")
+	}
+	i := Interrupt("stop")
+	if !errors.Is(i, CodeInterrupt.Sentinel()) || !errors.Is(i, context.Canceled) || errors.Is(i, CodeDefect.Sentinel()) {
+		t.Fatalf("interrupt should match its sentinel and its cause")
+	}
+}
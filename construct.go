@@ -45,15 +45,21 @@ func msgOrDefaultInternal(msg string) string {
 // failureErr represents an expected, recoverable domain/infrastructure failure.
 // Example: not_found, invalid, unavailable.
 type failureErr struct {
-	msg   string
-	code  Code
-	ctx   fields
-	cause error
-	stk   Stack
+	msg     string
+	code    Code
+	ctx     fields
+	cause   error
+	stk     Stack
+	details []any     // typed payloads, oldest first (see details.go)
+	trace   []uintptr // return trace, oldest first (see returntrace.go)
+	tag     bool      // built by Adopt: fields only, no message or code of its own
 }
 
 func (e *failureErr) Error() string {
 	if e.msg == "" {
+		if e.isTag() {
+			return e.cause.Error() // the cause supplies the text (see Adopt)
+		}
 		if e.code != "" {
 			return string(e.code)
 		}
@@ -69,6 +75,10 @@ func (e *failureErr) Unwrap() error           { return e.cause }
 func (e *failureErr) CodeVal() Code           { return e.code }
 func (e *failureErr) Context() map[string]any { return ctxToMap(e.ctx) }
 
+// Is matches the sentinel (see Code.Sentinel) of this failure's code or of
+// any ancestor: a "not_found.user" failure IS CodeNotFound.Sentinel().
+func (e *failureErr) Is(target error) bool { return matchesCodeSentinel(e.code, target) }
+
 // forEachField provides a package-private, zero-alloc iterator over fields.
 // It iterates from newest to oldest (reverse order) so callers can honor
 // last-write-wins by stopping at the first match.
@@ -95,16 +105,16 @@ func (e *failureErr) lookupFieldLast(key string) (any, bool) {
 // -------- Message API --------
 
 func (e *failureErr) MsgReplace(msg string) Error {
-	n := e.clone()
+	n := e.cloneAt(0)
 	n.msg = msg
 	return n
 }
 
 func (e *failureErr) MsgAppend(msg string) Error {
 	if msg == "" {
-		return e.clone()
+		return e.cloneAt(0)
 	}
-	n := e.clone()
+	n := e.cloneAt(0)
 	if n.msg == "" {
 		n.msg = msg
 	} else {
@@ -119,7 +129,7 @@ func (e *failureErr) MsgAppend(msg string) Error {
 // sets it to the provided msg. It does NOT concatenate messages.
 // Use context fields for progressive detail rather than string chaining.
 func (e *failureErr) Ctx(msg string, kv ...any) Error {
-	n := e.clone()
+	n := e.cloneAt(0)
 	if msg != "" && n.msg == "" {
 		n.msg = msg
 	}
@@ -130,8 +140,10 @@ func (e *failureErr) Ctx(msg string, kv ...any) Error {
 }
 
 // CtxBound behaves like Ctx but enforces a maximum number of TOTAL context
-// fields. When the total would exceed maxFields, it keeps the newest fields and
-// drops the oldest until total <= maxFields. If maxFields <= 0, no bound is applied.
+// fields. When the total would exceed maxFields, it drops the oldest UNPINNED
+// fields until total <= maxFields. Pinned fields (WithPinned, FieldPinned, CtxFrom) are never
+// dropped, so the total may stay above maxFields. If maxFields <= 0, no bound is
+// applied.
 //
 // Example:
 //
@@ -139,49 +151,55 @@ func (e *failureErr) Ctx(msg string, kv ...any) Error {
 //
 // Guidance:
 //
-//	// For must-keep identifiers (e.g., request_id, tenant), prefer typed fields.
+//	// For must-keep identifiers (e.g., request_id, tenant), pin them: WithPinned,
+//	// a TypedField declared with FieldPinned, or CtxFrom. Typed fields alone are
+//	// stored like any other field and evicted in turn.
 //
 // Message semantics are identical to Ctx: no concatenation; set once if empty.
 func (e *failureErr) CtxBound(msg string, maxFields int, kv ...any) Error {
-	n := e.clone()
+	n := e.cloneAt(0)
 	if msg != "" && n.msg == "" {
 		n.msg = msg
 	}
 	if len(kv) > 0 {
 		n.ctx = ctxCloneAppend(n.ctx, ctxFromKV(kv...)...)
 	}
-	if maxFields > 0 && len(n.ctx) > maxFields {
-		keep := n.ctx[len(n.ctx)-maxFields:]
-		// Defensive copy to ensure isolation even if the original had spare capacity.
-		copied := make(fields, len(keep))
-		copy(copied, keep)
-		n.ctx = copied
-	}
+	n.ctx = ctxBound(n.ctx, maxFields)
 	return n
 }
 
 func (e *failureErr) With(key string, val any) Error {
-	n := e.clone()
-	n.ctx = ctxCloneAppend(n.ctx, Field{Key: key, Val: val})
+	n := e.cloneAt(0)
+	n.ctx = ctxCloneAppend(n.ctx, ctxField{Field: Field{Key: key, Val: val}})
 	return n
 }
 
 func (e *failureErr) Code(c Code) Error {
-	n := e.clone()
+	n := e.cloneAt(0)
 	n.code = c
 	return n
 }
 
 func (e *failureErr) WithStack() Error {
-	return e.WithStackSkip(0)
+	n := e.cloneAt(0)
+	n.stk = captureStackDefault(0) // first frame is this method, as before
+	return n
 }
 
 func (e *failureErr) WithStackSkip(skip int) Error {
-	n := e.clone()
+	n := e.cloneAt(skip)
 	n.stk = captureStackDefault(skip + 1) // +1 to skip this method
 	return n
 }
 
+// cloneAt clones e and, when return traces are on, records the caller of the
+// fluent method that called cloneAt (plus skip frames).
+func (e *failureErr) cloneAt(skip int) *failureErr {
+	n := e.clone()
+	n.trace = appendTrace(e.trace, skip+1)
+	return n
+}
+
 func (e *failureErr) clone() *failureErr {
 	n := *e
 	// defensively copy context slice to preserve immutability guarantees
@@ -192,17 +210,19 @@ func (e *failureErr) clone() *failureErr {
 	} else {
 		n.ctx = emptyFields
 	}
-	// Stack is an immutable value type (slice of frames); shallow copy is fine.
+	// Stack is an immutable value type (shared PCs/frames); shallow copy is fine.
 	return &n
 }
 
 // defectErr models an unexpected programming error (bug/invariant violation).
 // Always captures a stack at creation for debuggability.
 type defectErr struct {
-	msg   string
-	ctx   fields
-	cause error
-	stk   Stack
+	msg     string
+	ctx     fields
+	cause   error
+	stk     Stack
+	details []any
+	trace   []uintptr
 }
 
 func (e *defectErr) Error() string {
@@ -219,6 +239,10 @@ func (e *defectErr) Unwrap() error           { return e.cause }
 func (e *defectErr) CodeVal() Code           { return CodeDefect }
 func (e *defectErr) Context() map[string]any { return ctxToMap(e.ctx) }
 
+// Is matches CodeDefect.Sentinel() only; a defect carries no finer code, so
+// sentinels such as "defect.invariant" never match it.
+func (e *defectErr) Is(target error) bool { return matchesCodeSentinel(CodeDefect, target) }
+
 // forEachField: newest-to-oldest to preserve last-write-wins semantics.
 func (e *defectErr) forEachField(fn func(k string, v any) bool) {
 	for i := len(e.ctx) - 1; i >= 0; i-- {
@@ -243,16 +267,16 @@ func (e *defectErr) lookupFieldLast(key string) (any, bool) {
 // -------- Message API --------
 
 func (e *defectErr) MsgReplace(msg string) Error {
-	n := e.clone()
+	n := e.cloneAt(0)
 	n.msg = msg
 	return n
 }
 
 func (e *defectErr) MsgAppend(msg string) Error {
 	if msg == "" {
-		return e.clone()
+		return e.cloneAt(0)
 	}
-	n := e.clone()
+	n := e.cloneAt(0)
 	if n.msg == "" {
 		n.msg = msg
 	} else {
@@ -265,7 +289,7 @@ func (e *defectErr) MsgAppend(msg string) Error {
 
 // Ctx: identical message semantics to failureErr — no concatenation.
 func (e *defectErr) Ctx(msg string, kv ...any) Error {
-	n := e.clone()
+	n := e.cloneAt(0)
 	if msg != "" && n.msg == "" {
 		n.msg = msg
 	}
@@ -283,37 +307,40 @@ func (e *defectErr) Ctx(msg string, kv ...any) Error {
 //
 // Guidance:
 //
-//	// For must-keep identifiers (e.g., request_id, tenant), prefer typed fields.
+//	// For must-keep identifiers (e.g., request_id, tenant), pin them: WithPinned,
+//	// a TypedField declared with FieldPinned, or CtxFrom. Typed fields alone are
+//	// stored like any other field and evicted in turn.
 func (e *defectErr) CtxBound(msg string, maxFields int, kv ...any) Error {
-	n := e.clone()
+	n := e.cloneAt(0)
 	if msg != "" && n.msg == "" {
 		n.msg = msg
 	}
 	if len(kv) > 0 {
 		n.ctx = ctxCloneAppend(n.ctx, ctxFromKV(kv...)...)
 	}
-	if maxFields > 0 && len(n.ctx) > maxFields {
-		keep := n.ctx[len(n.ctx)-maxFields:]
-		copied := make(fields, len(keep))
-		copy(copied, keep)
-		n.ctx = copied
-	}
+	n.ctx = ctxBound(n.ctx, maxFields)
 	return n
 }
 
 func (e *defectErr) With(key string, val any) Error {
-	n := e.clone()
-	n.ctx = ctxCloneAppend(n.ctx, Field{Key: key, Val: val})
+	n := e.cloneAt(0)
+	n.ctx = ctxCloneAppend(n.ctx, ctxField{Field: Field{Key: key, Val: val}})
 	return n
 }
 
 // Code ignores attempts to reclassify a defect. Defects are permanently
 // CodeDefect to preserve invariants, so this returns a clone without applying
 // the supplied code.
-func (e *defectErr) Code(c Code) Error { return e.clone() }
+func (e *defectErr) Code(c Code) Error { return e.cloneAt(0) }
+
+func (e *defectErr) WithStack() Error             { return e.cloneAt(0) }    // captured at creation
+func (e *defectErr) WithStackSkip(skip int) Error { return e.cloneAt(skip) } // do not recapture
 
-func (e *defectErr) WithStack() Error        { return e.clone() } // captured at creation
-func (e *defectErr) WithStackSkip(int) Error { return e.clone() } // do not recapture
+func (e *defectErr) cloneAt(skip int) *defectErr {
+	n := e.clone()
+	n.trace = appendTrace(e.trace, skip+1)
+	return n
+}
 
 func (e *defectErr) clone() *defectErr {
 	n := *e
@@ -329,9 +356,11 @@ func (e *defectErr) clone() *defectErr {
 // interruptErr models cooperative cancellation/timeouts. It unwraps to the
 // canonical context error so errors.Is(err, context.Canceled) works.
 type interruptErr struct {
-	msg   string
-	ctx   fields
-	cause error // either context.Canceled or context.DeadlineExceeded
+	msg     string
+	ctx     fields
+	cause   error // either context.Canceled or context.DeadlineExceeded
+	details []any
+	trace   []uintptr
 }
 
 func (e *interruptErr) Error() string {
@@ -345,6 +374,10 @@ func (e *interruptErr) Unwrap() error           { return e.cause }
 func (e *interruptErr) CodeVal() Code           { return CodeInterrupt }
 func (e *interruptErr) Context() map[string]any { return ctxToMap(e.ctx) }
 
+// Is matches CodeInterrupt.Sentinel(). The cancellation cause is matched
+// separately: errors.Is(err, context.Canceled) reaches it through Unwrap.
+func (e *interruptErr) Is(target error) bool { return matchesCodeSentinel(CodeInterrupt, target) }
+
 // forEachField: newest-to-oldest to preserve last-write-wins semantics.
 func (e *interruptErr) forEachField(fn func(k string, v any) bool) {
 	for i := len(e.ctx) - 1; i >= 0; i-- {
@@ -369,16 +402,16 @@ func (e *interruptErr) lookupFieldLast(key string) (any, bool) {
 // -------- Message API --------
 
 func (e *interruptErr) MsgReplace(msg string) Error {
-	n := e.clone()
+	n := e.cloneAt(0)
 	n.msg = msg
 	return n
 }
 
 func (e *interruptErr) MsgAppend(msg string) Error {
 	if msg == "" {
-		return e.clone()
+		return e.cloneAt(0)
 	}
-	n := e.clone()
+	n := e.cloneAt(0)
 	if n.msg == "" {
 		n.msg = msg
 	} else {
@@ -391,7 +424,7 @@ func (e *interruptErr) MsgAppend(msg string) Error {
 
 // Ctx: identical message semantics — no concatenation.
 func (e *interruptErr) Ctx(msg string, kv ...any) Error {
-	n := e.clone()
+	n := e.cloneAt(0)
 	if msg != "" && n.msg == "" {
 		n.msg = msg
 	}
@@ -402,8 +435,10 @@ func (e *interruptErr) Ctx(msg string, kv ...any) Error {
 }
 
 // CtxBound behaves like Ctx but enforces a maximum number of TOTAL context
-// fields. When the total would exceed maxFields, it keeps the newest fields and
-// drops the oldest until total <= maxFields. If maxFields <= 0, no bound is applied.
+// fields. When the total would exceed maxFields, it drops the oldest UNPINNED
+// fields until total <= maxFields. Pinned fields (WithPinned, FieldPinned, CtxFrom) are never
+// dropped, so the total may stay above maxFields. If maxFields <= 0, no bound is
+// applied.
 //
 // Example:
 //
@@ -411,33 +446,36 @@ func (e *interruptErr) Ctx(msg string, kv ...any) Error {
 //
 // Guidance:
 //
-//	// For must-keep identifiers (e.g., request_id, tenant), prefer typed fields.
+//	// For must-keep identifiers (e.g., request_id, tenant), pin them: WithPinned,
+//	// a TypedField declared with FieldPinned, or CtxFrom. Typed fields alone are
+//	// stored like any other field and evicted in turn.
 func (e *interruptErr) CtxBound(msg string, maxFields int, kv ...any) Error {
-	n := e.clone()
+	n := e.cloneAt(0)
 	if msg != "" && n.msg == "" {
 		n.msg = msg
 	}
 	if len(kv) > 0 {
 		n.ctx = ctxCloneAppend(n.ctx, ctxFromKV(kv...)...)
 	}
-	if maxFields > 0 && len(n.ctx) > maxFields {
-		keep := n.ctx[len(n.ctx)-maxFields:]
-		copied := make(fields, len(keep))
-		copy(copied, keep)
-		n.ctx = copied
-	}
+	n.ctx = ctxBound(n.ctx, maxFields)
 	return n
 }
 
 func (e *interruptErr) With(key string, val any) Error {
-	n := e.clone()
-	n.ctx = ctxCloneAppend(n.ctx, Field{Key: key, Val: val})
+	n := e.cloneAt(0)
+	n.ctx = ctxCloneAppend(n.ctx, ctxField{Field: Field{Key: key, Val: val}})
 	return n
 }
 
-func (e *interruptErr) Code(c Code) Error       { return e.clone() } // fixed class
-func (e *interruptErr) WithStack() Error        { return e.clone() } // no stacks for interrupts
-func (e *interruptErr) WithStackSkip(int) Error { return e.clone() }
+func (e *interruptErr) Code(c Code) Error            { return e.cloneAt(0) } // fixed class
+func (e *interruptErr) WithStack() Error             { return e.cloneAt(0) } // no stacks for interrupts
+func (e *interruptErr) WithStackSkip(skip int) Error { return e.cloneAt(skip) }
+
+func (e *interruptErr) cloneAt(skip int) *interruptErr {
+	n := e.clone()
+	n.trace = appendTrace(e.trace, skip+1)
+	return n
+}
 
 func (e *interruptErr) clone() *interruptErr {
 	n := *e
@@ -523,7 +561,8 @@ func Internal(err error) Error {
 		ctx:   emptyFields,
 		cause: err,
 	}
-	return fe.WithStack() // capture once at the boundary
+	fe.stk = captureStackDefault(0) // capture once at the boundary
+	return fe
 }
 
 // Timeout indicates operation took longer than expected. Records duration.
@@ -597,18 +636,18 @@ func Ctx(err error, msg string, kv ...any) Error {
 			msg:  msgOrDefaultInternal(msg),
 			code: CodeInternal,
 			ctx:  ctxFromKV(kv...),
-		}).clone()
+		}).cloneAt(0)
 	}
 	if xe, ok := err.(Error); ok {
 		// Respect set-once semantics inside implementation; do not force a default here.
-		return xe.Ctx(msg, kv...)
+		return retrace(xe.Ctx(msg, kv...), 0)
 	}
 	return (&failureErr{
 		msg:   msgOrDefaultInternal(msg),
 		code:  CodeInternal,
 		ctx:   ctxFromKV(kv...),
 		cause: err,
-	}).clone()
+	}).cloneAt(0)
 }
 
 // New creates a new internal failure with a message and optional context.
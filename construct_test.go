@@ -118,7 +118,7 @@ func TestInternal_StackAndCause(t *testing.T) {
 		if f.code != CodeInternal {
 			t.Fatalf("code: want=%s got=%s", CodeInternal, f.code)
 		}
-		if len(f.stk) == 0 {
+		if f.stk.Empty() {
 			t.Fatalf("expected stack to be captured for Internal(nil)")
 		}
 		if f.cause != nil {
@@ -133,7 +133,7 @@ func TestInternal_StackAndCause(t *testing.T) {
 		if !errors.Is(f, cause) {
 			t.Fatalf("expected errors.Is to match cause")
 		}
-		if len(f.stk) == 0 {
+		if f.stk.Empty() {
 			t.Fatalf("expected stack to be captured for Internal(cause)")
 		}
 	})
@@ -151,7 +151,7 @@ func TestDefect_Behavior(t *testing.T) {
 		if msg := d.Error(); msg != "defect: nil defect" {
 			t.Fatalf("defect error text mismatch: got %q", msg)
 		}
-		if len(d.stk) == 0 {
+		if d.stk.Empty() {
 			t.Fatalf("defect must capture stack at creation")
 		}
 	})
@@ -163,7 +163,7 @@ func TestDefect_Behavior(t *testing.T) {
 		if !errors.Is(d, cause) {
 			t.Fatalf("expected errors.Is to match cause")
 		}
-		if len(d.stk) == 0 {
+		if d.stk.Empty() {
 			t.Fatalf("defect must capture stack at creation")
 		}
 	})
@@ -274,11 +274,11 @@ func TestWithStack_BehaviorPerType(t *testing.T) {
 	// Failure: captures stack
 	f0 := asFailure(t, BadRequest("x"))
 	f1 := asFailure(t, f0.WithStack())
-	if len(f1.stk) == 0 {
+	if f1.stk.Empty() {
 		t.Fatalf("failure WithStack must capture stack")
 	}
 	// original unchanged
-	if len(f0.stk) != 0 {
+	if !f0.stk.Empty() {
 		t.Fatalf("original failure must remain without stack")
 	}
 
@@ -289,7 +289,7 @@ func TestWithStack_BehaviorPerType(t *testing.T) {
 		t.Fatalf("WithStack should return a clone (new pointer)")
 	}
 	// we cannot easily compare stacks by pointer, but length should remain > 0
-	if len(d1.stk) == 0 || len(d0.stk) == 0 {
+	if d1.stk.Empty() || d0.stk.Empty() {
 		t.Fatalf("defect stacks must exist")
 	}
 
@@ -4,7 +4,8 @@
 //   - Set-once message (only if empty) and always add fields.
 //
 // Design:
-//   - Internal representation: append-only []Field (deterministic order).
+//   - Internal representation: append-only []ctxField (deterministic order;
+//     each entry is a Field plus its pin state).
 //   - Builders are non-mutating: return NEW slices (no aliasing).
 //   - Public view for callers: copy-on-read map[string]any.
 //
@@ -24,9 +25,17 @@ type Field struct {
 	Val any
 }
 
+// ctxField is a Field as stored on a node, plus whether CtxBound must keep it.
+// Pin state lives here rather than on Field so the public struct stays a plain
+// key/value pair.
+type ctxField struct {
+	Field
+	pinned bool // survives CtxBound truncation (see ctxBound)
+}
+
 // fields is the internal immutable representation of context.
 // Treat it as append-only; never modify elements in place once published.
-type fields []Field
+type fields []ctxField
 
 // emptyFields is a canonical empty context.
 var emptyFields = make(fields, 0)
@@ -39,7 +48,7 @@ var emptyFields = make(fields, 0)
 //   - If dst is non-empty → return dst as-is (no allocation, no copy).
 //     This is safe because callers MUST NOT mutate returned slices.
 //   - If add is non-empty: allocate a fresh backing array to avoid aliasing.
-func ctxCloneAppend(dst fields, add ...Field) fields {
+func ctxCloneAppend(dst fields, add ...ctxField) fields {
 	n := len(dst)
 	m := len(add)
 	if m == 0 {
@@ -96,7 +105,7 @@ func ctxFromKV(kv ...any) fields {
 			// Trailing key with no value → nil
 			i++
 		}
-		out = append(out, Field{Key: k, Val: v})
+		out = append(out, ctxField{Field: Field{Key: k, Val: v}})
 	}
 	if len(out) == 0 {
 		return emptyFields
@@ -104,18 +113,39 @@ func ctxFromKV(kv ...any) fields {
 	return out
 }
 
+// ctxBound trims fs to at most maxFields by dropping the OLDEST unpinned
+// fields. Pinned fields are never dropped, so the result exceeds maxFields when
+// more than maxFields are pinned. If maxFields <= 0 or fs already fits, fs is
+// returned as-is; otherwise the result is a fresh slice (no aliasing).
+func ctxBound(fs fields, maxFields int) fields {
+	if maxFields <= 0 || len(fs) <= maxFields {
+		return fs
+	}
+	drop := len(fs) - maxFields
+	out := make(fields, 0, maxFields)
+	for _, f := range fs {
+		if drop > 0 && !f.pinned {
+			drop--
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
 // ctxToMap creates a NEW map from fields (copy-on-read).
 // Semantics:
 //   - Always returns a non-nil map (safe for mutation by the caller).
 //   - Later duplicate keys overwrite earlier ones (last-write-wins).
 //   - Empty keys are filtered out to avoid polluting caller maps.
+//   - Sensitive fields (see redact.go) are rendered as RedactedText.
 func ctxToMap(fs fields) map[string]any {
 	m := make(map[string]any, len(fs))
 	for _, f := range fs {
 		if f.Key == "" {
 			continue // filter empty keys
 		}
-		m[f.Key] = f.Val
+		m[f.Key] = f.Redacted().Val
 	}
 	return m
 }
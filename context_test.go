@@ -6,6 +6,15 @@ import (
 	"testing"
 )
 
+// fieldsOf builds unpinned internal fields from fs.
+func fieldsOf(fs ...Field) fields {
+	out := make(fields, len(fs))
+	for i, f := range fs {
+		out[i] = ctxField{Field: f}
+	}
+	return out
+}
+
 func TestCtxFromKV_EmptyInputReturnsEmptyFields(t *testing.T) {
 	t.Parallel()
 
@@ -23,7 +32,7 @@ func TestCtxFromKV_ValidPairsPreserveOrder(t *testing.T) {
 	t.Parallel()
 
 	fs := ctxFromKV("k1", 1, "k2", 2, "k3", 3)
-	want := fields{{Key: "k1", Val: 1}, {Key: "k2", Val: 2}, {Key: "k3", Val: 3}}
+	want := fieldsOf(Field{Key: "k1", Val: 1}, Field{Key: "k2", Val: 2}, Field{Key: "k3", Val: 3})
 
 	if !reflect.DeepEqual(fs, want) {
 		t.Fatalf("order mismatch.\nwant=%#v\ngot =%#v", want, fs)
@@ -34,7 +43,7 @@ func TestCtxFromKV_NonStringKeyDropsEntirePair(t *testing.T) {
 	t.Parallel()
 
 	fs := ctxFromKV(123, "v1", "k2", "v2")
-	want := fields{{Key: "k2", Val: "v2"}}
+	want := fieldsOf(Field{Key: "k2", Val: "v2"})
 
 	if !reflect.DeepEqual(fs, want) {
 		t.Fatalf("non-string key should drop whole pair.\nwant=%#v\ngot =%#v", want, fs)
@@ -58,7 +67,7 @@ func TestCtxFromKV_MixedValidInvalidAlignsCorrectly(t *testing.T) {
 
 	// "a",1, 123,"x", "b",2   => drop (123,"x")
 	fs := ctxFromKV("a", 1, 123, "x", "b", 2)
-	want := fields{{Key: "a", Val: 1}, {Key: "b", Val: 2}}
+	want := fieldsOf(Field{Key: "a", Val: 1}, Field{Key: "b", Val: 2})
 
 	if !reflect.DeepEqual(fs, want) {
 		t.Fatalf("alignment broken.\nwant=%#v\ngot =%#v", want, fs)
@@ -69,7 +78,7 @@ func TestCtxCloneAppend_EmptyAddReturnsDstDirectly_NoAllocWhenPossible(t *testin
 	t.Parallel()
 
 	// Non-empty dst: should return the same slice header/backing (no copy).
-	dst := fields{{Key: "k1", Val: 1}, {Key: "k2", Val: 2}}
+	dst := fieldsOf(Field{Key: "k1", Val: 1}, Field{Key: "k2", Val: 2})
 	got := ctxCloneAppend(dst /* add empty */)
 
 	if len(got) != len(dst) {
@@ -91,8 +100,8 @@ func TestCtxCloneAppend_EmptyAddReturnsDstDirectly_NoAllocWhenPossible(t *testin
 func TestCtxCloneAppend_NonEmptyAddAllocatesFreshBacking(t *testing.T) {
 	t.Parallel()
 
-	dst := fields{{Key: "k1", Val: 1}}
-	add := []Field{{Key: "k2", Val: 2}}
+	dst := fieldsOf(Field{Key: "k1", Val: 1})
+	add := fieldsOf(Field{Key: "k2", Val: 2})
 
 	got := ctxCloneAppend(dst, add...)
 	if len(got) != 2 {
@@ -107,8 +116,8 @@ func TestCtxCloneAppend_NonEmptyAddAllocatesFreshBacking(t *testing.T) {
 func TestCtxCloneAppend_NoAliasingOnReturnedSlice(t *testing.T) {
 	t.Parallel()
 
-	dst := fields{{Key: "k1", Val: 1}}
-	add := []Field{{Key: "k2", Val: 2}}
+	dst := fieldsOf(Field{Key: "k1", Val: 1})
+	add := fieldsOf(Field{Key: "k2", Val: 2})
 	got := ctxCloneAppend(dst, add...)
 
 	// Mutate returned slice; original must remain unchanged.
@@ -133,10 +142,10 @@ func TestCtxToMap_AlwaysNonNil(t *testing.T) {
 func TestCtxToMap_FiltersEmptyKeys(t *testing.T) {
 	t.Parallel()
 
-	fs := fields{
-		{Key: "", Val: "drop-me"},
-		{Key: "k", Val: "v"},
-	}
+	fs := fieldsOf(
+		Field{Key: "", Val: "drop-me"},
+		Field{Key: "k", Val: "v"},
+	)
 	m := ctxToMap(fs)
 
 	if _, ok := m[""]; ok {
@@ -150,11 +159,11 @@ func TestCtxToMap_FiltersEmptyKeys(t *testing.T) {
 func TestCtxToMap_LastWriteWinsForDuplicates(t *testing.T) {
 	t.Parallel()
 
-	fs := fields{
-		{Key: "dup", Val: 1},
-		{Key: "dup", Val: 2},
-		{Key: "dup", Val: 3},
-	}
+	fs := fieldsOf(
+		Field{Key: "dup", Val: 1},
+		Field{Key: "dup", Val: 2},
+		Field{Key: "dup", Val: 3},
+	)
 	m := ctxToMap(fs)
 
 	if len(m) != 1 {
@@ -168,10 +177,10 @@ func TestCtxToMap_LastWriteWinsForDuplicates(t *testing.T) {
 func TestCtxToMap_DefensiveCopy(t *testing.T) {
 	t.Parallel()
 
-	fs := fields{
-		{Key: "a", Val: 1},
-		{Key: "b", Val: 2},
-	}
+	fs := fieldsOf(
+		Field{Key: "a", Val: 1},
+		Field{Key: "b", Val: 2},
+	)
 	m1 := ctxToMap(fs)
 	// Mutate m1; calling ctxToMap again must not be affected.
 	m1["a"] = 999
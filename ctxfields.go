@@ -0,0 +1,210 @@
+// ctxfields.go — request-scoped fields carried by context.Context.
+//
+// Request ids, tenants and trace ids are known once per request but needed on
+// every error. Instead of threading them through Ctx(...) at each call site,
+// put them on the context once and attach them where errors are created or
+// returned:
+//
+//	ctx = xgxerror.WithContextFields(ctx, "request_id", rid, "tenant", t)
+//	...
+//	return xgxerror.NotFoundCtx(ctx, "user", id)
+//	return xgxerror.CtxFrom(ctx, err)
+//
+// Extractors pull fields out of contexts populated by other libraries (e.g. a
+// tracing span) without touching their call sites:
+//
+//	xgxerror.RegisterContextExtractor("otel", func(ctx context.Context) []xgxerror.Field {
+//	    sc := trace.SpanContextFromContext(ctx)
+//	    if !sc.IsValid() {
+//	        return nil
+//	    }
+//	    return []xgxerror.Field{{Key: "trace_id", Val: sc.TraceID().String()}}
+//	})
+//
+// Semantics:
+//   - FromContext returns extractor fields (registration order) followed by
+//     WithContextFields fields (outermost call last), so explicit values win
+//     under last-write-wins.
+//   - Fields attached from a context are PINNED: CtxBound never evicts them.
+//   - CtxFrom skips a field the node already carries pinned with an equal
+//     value, so attaching at several layers does not duplicate it.
+package xgxerror
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// ContextExtractor returns request-scoped fields found in ctx (nil if none).
+// It must be safe for concurrent use and cheap: it runs on every CtxFrom.
+type ContextExtractor func(ctx context.Context) []Field
+
+type namedExtractor struct {
+	name string
+	fn   ContextExtractor
+}
+
+var (
+	extractorsMu sync.Mutex                       // serializes writers
+	extractors   atomic.Pointer[[]namedExtractor] // copy-on-write; nil = none
+)
+
+// RegisterContextExtractor installs fn under name, replacing any extractor
+// already registered under that name (in place, keeping its order). A nil fn
+// removes the extractor. Safe for concurrent use.
+func RegisterContextExtractor(name string, fn ContextExtractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+
+	var cur []namedExtractor
+	if p := extractors.Load(); p != nil {
+		cur = *p
+	}
+	next := make([]namedExtractor, 0, len(cur)+1)
+	replaced := false
+	for _, x := range cur {
+		if x.name != name {
+			next = append(next, x)
+			continue
+		}
+		replaced = true
+		if fn != nil {
+			next = append(next, namedExtractor{name: name, fn: fn})
+		}
+	}
+	if !replaced && fn != nil {
+		next = append(next, namedExtractor{name: name, fn: fn})
+	}
+	extractors.Store(&next)
+}
+
+// ContextExtractors returns the names of the registered extractors in the
+// order they run.
+func ContextExtractors() []string {
+	p := extractors.Load()
+	if p == nil {
+		return nil
+	}
+	out := make([]string, len(*p))
+	for i, x := range *p {
+		out[i] = x.name
+	}
+	return out
+}
+
+// ctxFieldsKey is the context key for fields added by WithContextFields.
+type ctxFieldsKey struct{}
+
+// WithContextFields returns a copy of ctx carrying kv as request-scoped
+// fields, after any already present. Pairs follow the Ctx rules (non-string
+// keys drop their pair; a trailing key gets nil).
+func WithContextFields(ctx context.Context, kv ...any) context.Context {
+	add := ctxFromKV(kv...)
+	if len(add) == 0 {
+		return ctx
+	}
+	prev, _ := ctx.Value(ctxFieldsKey{}).(fields)
+	return context.WithValue(ctx, ctxFieldsKey{}, ctxCloneAppend(prev, add...))
+}
+
+// FromContext returns the request-scoped fields for ctx: those produced by
+// registered extractors, then those added with WithContextFields. It returns
+// nil for a nil ctx or when there are none. The result is a fresh slice.
+func FromContext(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	var out []Field
+	if p := extractors.Load(); p != nil {
+		for _, x := range *p {
+			for _, f := range x.fn(ctx) {
+				out = append(out, Field{Key: f.Key, Val: f.Val})
+			}
+		}
+	}
+	if fs, _ := ctx.Value(ctxFieldsKey{}).(fields); len(fs) > 0 {
+		for _, f := range fs {
+			out = append(out, Field{Key: f.Key, Val: f.Val})
+		}
+	}
+	return out
+}
+
+// CtxFrom attaches ctx's request-scoped fields (see FromContext) to err as
+// pinned fields and returns the result.
+//   - nil err → nil, so `return CtxFrom(ctx, err)` is safe on every path.
+//   - native Error → copy of err with the fields appended (err itself when
+//     there are none).
+//   - other → wrapped in a tag carrying the fields, like Adopt: the text and
+//     classification of err show through unchanged. A third-party Error
+//     without fields to attach is returned as-is.
+func CtxFrom(ctx context.Context, err error) Error {
+	if err == nil {
+		return nil
+	}
+	fs := FromContext(ctx)
+	switch e := err.(type) {
+	case *failureErr:
+		if len(fs) == 0 {
+			return e
+		}
+		n := e.cloneAt(0)
+		n.ctx = ctxAppendPinned(n.ctx, fs)
+		return n
+	case *defectErr:
+		if len(fs) == 0 {
+			return e
+		}
+		n := e.cloneAt(0)
+		n.ctx = ctxAppendPinned(n.ctx, fs)
+		return n
+	case *interruptErr:
+		if len(fs) == 0 {
+			return e
+		}
+		n := e.cloneAt(0)
+		n.ctx = ctxAppendPinned(n.ctx, fs)
+		return n
+	case Error:
+		if len(fs) == 0 {
+			return e
+		}
+	}
+	return &failureErr{
+		ctx:   ctxAppendPinned(emptyFields, fs),
+		cause: err,
+		tag:   true,
+		trace: appendTrace(nil, 0),
+	}
+}
+
+// NotFoundCtx is NotFound carrying ctx's request-scoped fields (pinned).
+func NotFoundCtx(ctx context.Context, entity string, id any) Error {
+	e := NotFound(entity, id).(*failureErr)
+	e.ctx = ctxAppendPinned(e.ctx, FromContext(ctx))
+	return e
+}
+
+// ctxAppendPinned returns dst plus add marked pinned, skipping fields dst
+// already carries pinned with an equal value. It never writes into dst.
+func ctxAppendPinned(dst fields, add []Field) fields {
+	pinned := make(fields, 0, len(add))
+	for _, f := range add {
+		if f.Key == "" || hasPinned(dst, f) || hasPinned(pinned, f) {
+			continue
+		}
+		pinned = append(pinned, ctxField{Field: f, pinned: true})
+	}
+	return ctxCloneAppend(dst, pinned...)
+}
+
+// hasPinned reports whether fs holds a pinned field with f's key and value.
+func hasPinned(fs fields, f Field) bool {
+	for _, g := range fs {
+		if g.pinned && g.Key == f.Key && safeEqual(g.Val, f.Val) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+// ctxfields_test.go — verification of context-propagated request fields.
+package xgxerror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// fieldKeys renders a node's own field keys in insertion order.
+func fieldKeys(e Error) []string {
+	var out []string
+	for k := range Fields(e) {
+		out = append(out, k)
+	}
+	return out
+}
+
+func TestWithContextFields_Accumulates(t *testing.T) {
+	t.Parallel()
+
+	ctx := WithContextFields(context.Background(), "request_id", "r1", 7, "dropped")
+	ctx = WithContextFields(ctx, "tenant", "acme")
+	if same := WithContextFields(ctx); same != ctx {
+		t.Fatalf("no fields should return ctx unchanged")
+	}
+	got := FromContext(ctx)
+	want := []Field{{Key: "request_id", Val: "r1"}, {Key: "tenant", Val: "acme"}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("FromContext = %v, want %v", got, want)
+	}
+	if FromContext(nil) != nil || FromContext(context.Background()) != nil {
+		t.Fatalf("no fields should yield nil")
+	}
+}
+
+func TestCtxFrom_AttachesPinnedFields(t *testing.T) {
+	t.Parallel()
+
+	ctx := WithContextFields(context.Background(), "request_id", "r1", "tenant", "acme")
+
+	if CtxFrom(ctx, nil) != nil {
+		t.Fatalf("nil err must stay nil")
+	}
+
+	e := CtxFrom(ctx, Conflict("dup"))
+	if CodeOf(e) != CodeConflict || !slices.Equal(fieldKeys(e), []string{"request_id", "tenant"}) {
+		t.Fatalf("native: %v %v", CodeOf(e), fieldKeys(e))
+	}
+	// Re-attaching at another layer must not duplicate the fields.
+	e = CtxFrom(ctx, e.Ctx("", "op", "save"))
+	if !slices.Equal(fieldKeys(e), []string{"request_id", "tenant", "op"}) {
+		t.Fatalf("duplicate attach: %v", fieldKeys(e))
+	}
+
+	foreign := fmt.Errorf("db: %w", Unavailable("pg"))
+	f := CtxFrom(ctx, foreign)
+	if !errors.Is(f, foreign) || CodeOf(f) != CodeUnavailable || f.Context()["tenant"] != "acme" {
+		t.Fatalf("foreign: %v code=%v ctx=%v", f, CodeOf(f), f.Context())
+	}
+
+	if f.Error() != foreign.Error() {
+		t.Fatalf("foreign text must show through: %q", f)
+	}
+	nf := CtxFrom(ctx, fmt.Errorf("repo: %w", NotFound("user", 42)))
+	if nf.Error() != "repo: not_found: user not found" || CodeOf(nf) != CodeNotFound {
+		t.Fatalf("foreign not_found: %q code=%v", nf, CodeOf(nf))
+	}
+
+	plain := errors.New("x")
+	if got := CtxFrom(context.Background(), plain); !errors.Is(got, plain) || len(got.Context()) != 0 ||
+		got.Error() != "x" || CodeOf(got) != "" {
+		t.Fatalf("without fields CtxFrom adds nothing: %q code=%q", got, CodeOf(got))
+	}
+	if ne := NotFound("user", 1); CtxFrom(context.Background(), ne) != ne {
+		t.Fatalf("native without fields should be returned as-is")
+	}
+}
+
+func TestCtxFrom_FieldsSurviveCtxBound(t *testing.T) {
+	t.Parallel()
+
+	ctx := WithContextFields(context.Background(), "request_id", "r1")
+	e := NotFoundCtx(ctx, "user", 42) // entity, id, request_id(pinned)
+	for i := 0; i < 5; i++ {
+		e = e.CtxBound("", 3, "attempt", i)
+	}
+	if !slices.Equal(fieldKeys(e), []string{"request_id", "attempt", "attempt"}) {
+		t.Fatalf("after bounded wraps: %v", fieldKeys(e))
+	}
+	if e.Context()["request_id"] != "r1" || e.Context()["attempt"] != 4 {
+		t.Fatalf("values: %v", e.Context())
+	}
+	if CodeOf(e) != CodeNotFound {
+		t.Fatalf("code: %v", CodeOf(e))
+	}
+}
+
+func TestRegisterContextExtractor(t *testing.T) {
+	// Not parallel: mutates the package-level extractor registry.
+	t.Cleanup(func() {
+		RegisterContextExtractor("trace", nil)
+		RegisterContextExtractor("tenant", nil)
+	})
+
+	type traceKey struct{}
+	RegisterContextExtractor("trace", func(ctx context.Context) []Field {
+		if id, ok := ctx.Value(traceKey{}).(string); ok {
+			return []Field{{Key: "trace_id", Val: id}}
+		}
+		return nil
+	})
+	RegisterContextExtractor("tenant", func(context.Context) []Field {
+		return []Field{{Key: "tenant", Val: "default"}}
+	})
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "t-1")
+	ctx = WithContextFields(ctx, "tenant", "acme")
+	e := CtxFrom(ctx, BadRequest("x"))
+	if !slices.Equal(fieldKeys(e), []string{"trace_id", "tenant", "tenant"}) || e.Context()["tenant"] != "acme" {
+		t.Fatalf("extractors run first, explicit fields win: %v %v", fieldKeys(e), e.Context())
+	}
+
+	// Replacing keeps the position; nil removes.
+	RegisterContextExtractor("trace", func(context.Context) []Field { return nil })
+	if got := ContextExtractors(); !slices.Equal(got, []string{"trace", "tenant"}) {
+		t.Fatalf("order after replace: %v", got)
+	}
+	RegisterContextExtractor("trace", nil)
+	if got := ContextExtractors(); !slices.Equal(got, []string{"tenant"}) {
+		t.Fatalf("after remove: %v", got)
+	}
+}
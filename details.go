@@ -0,0 +1,115 @@
+// details.go — typed detail payloads attached to errors (gRPC "details" style).
+//
+// A detail is any Go value (a retry hint, a quota-failure struct, a list of
+// violations) attached to an error node and retrieved BY TYPE, so teams do not
+// have to agree on key strings the way TypedField does.
+//
+// Semantics:
+//   - WithDetail appends to the outermost native node (copy-on-write); foreign
+//     errors are wrapped in a tag first, like Adopt, so their text and code
+//     are unchanged.
+//   - DetailOf/DetailsOf search the whole unwrap graph via Walk, pre-order;
+//     within a node the newest detail comes first. DetailOf returns the first
+//     match, so the outermost, most recent value wins.
+//   - T may be an interface type (e.g. DetailOf[fmt.Stringer]).
+//   - Details render in %+v ("details:") and travel through Inspect/Restore,
+//     so exporters (jsonx, slog) include them. Wrap values in Secret to keep
+//     them out of rendered output.
+package xgxerror
+
+// WithDetail attaches v to err immutably and returns the result.
+//   - nil err → new internal failure carrying v (same as With(nil, ...)).
+//   - native Error → copy of err with v appended to its details.
+//   - other → wrapped in a tag carrying v (see Adopt).
+//
+// A nil v attaches nothing: a native err comes back as a copy, a foreign one
+// as a bare tag, and a nil err as a new internal failure.
+func WithDetail(err error, v any) Error {
+	switch e := err.(type) {
+	case *failureErr:
+		n := e.cloneAt(0)
+		n.details = appendDetail(n.details, v)
+		return n
+	case *defectErr:
+		n := e.cloneAt(0)
+		n.details = appendDetail(n.details, v)
+		return n
+	case *interruptErr:
+		n := e.cloneAt(0)
+		n.details = appendDetail(n.details, v)
+		return n
+	case nil:
+		return &failureErr{msg: "error", code: CodeInternal, ctx: emptyFields, details: appendDetail(nil, v), trace: appendTrace(nil, 0)}
+	}
+	if xe, ok := err.(Error); ok && v == nil {
+		return xe
+	}
+	// Foreign errors and third-party Error implementations: wrap in a tag (see
+	// Adopt) so the detail has a native node to live on without changing the
+	// text or classification of err.
+	return &failureErr{
+		ctx:     emptyFields,
+		cause:   err,
+		details: appendDetail(nil, v),
+		tag:     true,
+		trace:   appendTrace(nil, 0),
+	}
+}
+
+// DetailOf returns the first detail of type T in err's graph (outermost node
+// first, newest detail first within a node).
+func DetailOf[T any](err error) (T, bool) {
+	var (
+		out   T
+		found bool
+	)
+	Walk(err, func(e error) bool {
+		ds := nodeDetails(e)
+		for i := len(ds) - 1; i >= 0; i-- {
+			if v, ok := ds[i].(T); ok {
+				out, found = v, true
+				return false
+			}
+		}
+		return true
+	})
+	return out, found
+}
+
+// DetailsOf returns every detail of type T in err's graph, in DetailOf order.
+func DetailsOf[T any](err error) []T {
+	var out []T
+	Walk(err, func(e error) bool {
+		ds := nodeDetails(e)
+		for i := len(ds) - 1; i >= 0; i-- {
+			if v, ok := ds[i].(T); ok {
+				out = append(out, v)
+			}
+		}
+		return true
+	})
+	return out
+}
+
+// nodeDetails returns a node's own details, oldest first (nil for foreign
+// and join nodes). Callers must not modify the result.
+func nodeDetails(err error) []any {
+	switch e := err.(type) {
+	case *failureErr:
+		return e.details
+	case *defectErr:
+		return e.details
+	case *interruptErr:
+		return e.details
+	}
+	return nil
+}
+
+// appendDetail returns ds plus v without writing into ds's backing array, so
+// clones sharing ds stay isolated. A nil v is ignored.
+func appendDetail(ds []any, v any) []any {
+	if v == nil {
+		return ds
+	}
+	return append(ds[:len(ds):len(ds)], v)
+}
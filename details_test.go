@@ -0,0 +1,114 @@
+// details_test.go — verification of typed detail payloads.
+package xgxerror
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+type retryHint struct{ After time.Duration }
+
+type quotaFailure struct {
+	Subject string
+	Limit   int
+}
+
+func (q quotaFailure) String() string { return fmt.Sprintf("quota %s/%d", q.Subject, q.Limit) }
+
+func TestWithDetail_AttachesAndRetrievesByType(t *testing.T) {
+	t.Parallel()
+
+	base := TooManyRequests("api")
+	e1 := WithDetail(base, retryHint{After: time.Second})
+	e2 := WithDetail(e1, quotaFailure{Subject: "user", Limit: 10})
+	e3 := WithDetail(e2, retryHint{After: 5 * time.Second})
+
+	if h, ok := DetailOf[retryHint](e3); !ok || h.After != 5*time.Second {
+		t.Fatalf("newest detail should win: %+v, %v", h, ok)
+	}
+	if hs := DetailsOf[retryHint](e3); len(hs) != 2 || hs[0].After != 5*time.Second || hs[1].After != time.Second {
+		t.Fatalf("DetailsOf order: %+v", hs)
+	}
+	if s, ok := DetailOf[fmt.Stringer](e3); !ok || s.String() != "quota user/10" {
+		t.Fatalf("interface lookups should match implementations: %v, %v", s, ok)
+	}
+	if _, ok := DetailOf[retryHint](base); ok || len(Inspect(e1).Details) != 1 {
+		t.Fatalf("WithDetail must not mutate its input")
+	}
+	if CodeOf(e3) != CodeTooManyRequests {
+		t.Fatalf("code changed: %v", CodeOf(e3))
+	}
+}
+
+func TestDetailOf_SearchesWholeGraph(t *testing.T) {
+	t.Parallel()
+
+	inner := WithDetail(Unavailable("db"), retryHint{After: time.Second})
+	wrapped := fmt.Errorf("repo: %w", inner)
+	joined := Join(NotFound("user", 1), WithDetail(Conflict("dup"), quotaFailure{Limit: 1}), wrapped)
+
+	if h, ok := DetailOf[retryHint](joined); !ok || h.After != time.Second {
+		t.Fatalf("detail behind Join and foreign wrapper not found")
+	}
+	if _, ok := DetailOf[quotaFailure](joined); !ok {
+		t.Fatalf("detail in second branch not found")
+	}
+	if _, ok := DetailOf[int](joined); ok || DetailsOf[int](nil) != nil {
+		t.Fatalf("absent types should not match")
+	}
+}
+
+func TestWithDetail_NilAndForeign(t *testing.T) {
+	t.Parallel()
+
+	if e := WithDetail(nil, retryHint{}); CodeOf(e) != CodeInternal || len(DetailsOf[retryHint](e)) != 1 {
+		t.Fatalf("nil err should create a failure carrying the detail: %v", e)
+	}
+
+	sentinel := errors.New("io")
+	e := WithDetail(sentinel, retryHint{After: time.Millisecond})
+	if !errors.Is(e, sentinel) || CodeOf(e) != "" || e.Error() != "io" {
+		t.Fatalf("foreign errors are tagged, not classified: %q code=%q", e, CodeOf(e))
+	}
+	got := WithDetail(fmt.Errorf("x: %w", NotFound("user", 1)), retryHint{})
+	if CodeOf(got) != CodeNotFound || got.Error() != "x: not_found: user not found" {
+		t.Fatalf("wrapping keeps the text and code found below: %q code=%v", got, CodeOf(got))
+	}
+
+	if got := WithDetail(sentinel, nil); len(Inspect(got).Details) != 0 || !errors.Is(got, sentinel) {
+		t.Fatalf("nil detail attaches nothing")
+	}
+}
+
+func TestDetails_RenderedAndRestored(t *testing.T) {
+	t.Parallel()
+
+	e := WithDetail(WithDetail(Invalid("email", "bad"), quotaFailure{Subject: "org", Limit: 3}), Secret("s3cr3t"))
+	out := fmt.Sprintf("%+v", e)
+	if !strings.Contains(out, "\ndetails:\n  xgxerror.quotaFailure quota org/3") {
+		t.Fatalf("%%+v should list details:\n%s", out)
+	}
+	if strings.Contains(out, "s3cr3t") {
+		t.Fatalf("secret detail leaked:\n%s", out)
+	}
+
+	r := Restore(Inspect(e))
+	if q, ok := DetailOf[quotaFailure](r); !ok || q.Limit != 3 {
+		t.Fatalf("Inspect/Restore should keep details")
+	}
+
+	v := LogValue(e, LogOptions{})
+	var found bool
+	for _, a := range v.Group() {
+		if a.Key == "details" && a.Value.Kind() == slog.KindAny {
+			found = len(a.Value.Any().([]any)) == 2
+		}
+	}
+	if !found {
+		t.Fatalf("slog output should carry details: %v", v)
+	}
+}
@@ -57,9 +57,13 @@
 //   - Example: given [a, b, c, d, e] and max=3 → keeps [c, d, e] (newest).
 //
 // Guidance:
-//   - For **must-keep IDs** (request_id, tenant), prefer **typed fields** and set them
-//     early; bounded context will still keep the most recent assignment.
+//   - For **must-keep IDs** (request_id, tenant), **pin** them (`WithPinned`, a
+//     `FieldPinned` typed field, or `CtxFrom`); `CtxBound` evicts unpinned fields
+//     first and never drops pinned ones.
 //   - Duplicate keys are allowed; “last write wins” when exposed via `Context()`.
+//   - Sensitive values (`Secret(v)`, `FieldSensitive`, or keys matched by
+//     `SetRedactedKeys`) render as `[REDACTED]` in `%+v`, `Context()` and exporters;
+//     `TypedField.Get` and `Reveal` are the privileged raw accessors.
 //
 // # Foreign Error Caveat
 //
@@ -92,7 +96,9 @@
 //   - **Typed fields**: zero-alloc fast path for native xgxerror values; on foreign
 //     errors, `TypedField.Get` falls back to `Context()` which builds a map (alloc).
 //   - **Stack capture**: costs only when you call `Internal/Defect` (always) or
-//     opt in with `WithStack()`.
+//     opt in with `WithStack()`; frames are symbolized only when read.
+//   - **Return traces**: `SetReturnTraces(true)` makes every wrap record one
+//     caller frame (see `ReturnTrace`); off by default.
 //   - **Formatting**: verbose `%+v` is lazy; concise `%v` remains cheap.
 //
 // # Interop
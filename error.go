@@ -9,7 +9,8 @@
 //
 // Design tenets:
 //   - Interop-first: play nicely with errors.Is/As and errors.Join.
-//   - Minimal surface: no logging/HTTP/JSON in core.
+//   - Minimal surface: no logging/HTTP/JSON policy in core (slog.LogValuer is
+//     the only logging hook; handlers live in slogx).
 //   - Non-mutating ergonomics: fluent builders return a new value.
 //   - Selective stacks: callers opt in; defects capture by default (impl detail).
 //
@@ -24,14 +25,16 @@
 //         it leaves the message untouched. Use MsgAppend/MsgReplace for message control.
 //       • MsgAppend: explicitly concatenates using ": " as a separator.
 //       • MsgReplace: explicitly overwrites the message.
+//       • A tag built by Adopt (no message, no code) renders as its cause's
+//         text; classification helpers look through it.
 //   - Context fields (Ctx/CtxBound/With): appended in call order as key/value
 //     pairs. Non-string "key" causes the entire pair (key and its following
 //     value, if any) to be dropped to avoid misalignment. A trailing key with
 //     no value is recorded as (key, nil).
 //   - Bounded context (CtxBound): enforces a maximum number of total fields;
-//     when exceeded, newest fields are kept and the oldest are dropped until
-//     total <= maxFields. New fields from kv are added first, then truncation
-//     is applied if needed.
+//     when exceeded, newest fields are kept and the oldest unpinned ones are
+//     dropped until total <= maxFields. New fields from kv are added first,
+//     then truncation is applied if needed.
 //   - Stack capture: WithStack() attempts to skip internal helpers so captured
 //     frames begin at or near the user call site. Depending on inlining and
 //     tooling, 1–2 boundary frames may still appear.
@@ -57,7 +60,8 @@ type Code string
 //     to integrate with errors.Is/As over joined error trees.
 //
 // Note: Core intentionally avoids logging/HTTP/JSON methods. Adapters live in
-// separate modules (e.g., xgx-error-slog, xgx-error-http, xgx-error-json).
+// subpackages (e.g., jsonx, slogx) or separate modules (e.g., xgx-error-http)
+// and read nodes through Inspect.
 type Error interface {
 	// error provides the canonical concise message string. Keep it concise;
 	// rich export (JSON, structured logs) belongs to adapters outside the core.
@@ -93,9 +97,9 @@ type Error interface {
 	Ctx(msg string, kv ...any) Error
 
 	// CtxBound behaves like Ctx but enforces a maximum number of total context
-	// fields. When the total would exceed maxFields, it keeps the newest fields
-	// and drops the oldest until total <= maxFields. If maxFields <= 0, no
-	// bound is applied. Returns a NEW Error.
+	// fields. When the total would exceed maxFields, it drops the oldest
+	// unpinned fields until total <= maxFields; pinned fields (see WithPinned) are
+	// never dropped. If maxFields <= 0, no bound is applied. Returns a NEW Error.
 	//
 	// Example:
 	//   err = err.CtxBound("retry", 8, "attempt", n, "backoff_ms", d.Milliseconds())
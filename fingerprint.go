@@ -0,0 +1,134 @@
+// fingerprint.go — stable error identities for grouping and deduplication.
+//
+// Fingerprint hashes what identifies a BUG rather than one occurrence of it:
+//   - the code graph: kind and code of every node Walk visits (the full Join
+//     graph, pre-order) plus each node's arity, so the shape matters too;
+//   - message templates: messages with volatile tokens (ids, numbers, UUIDs,
+//     addresses, quoted values) collapsed to placeholders (see msgTemplate);
+//   - the top fingerprintFrames in-module frames of every captured stack, by
+//     function name only (no files, lines or PCs).
+//
+// Context fields, return traces and StackPolicy never contribute, so the same
+// failure yields the same fingerprint across requests, builds and hosts.
+// Error() is unsuitable for this: it embeds ids and other per-call values.
+package xgxerror
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// fingerprintFrames caps in-module frames hashed per stack. Deeper frames
+// tend to be shared plumbing (routers, workers) rather than the bug itself.
+const fingerprintFrames = 5
+
+// Fingerprint returns a stable 16-hex-digit identity for err, suitable for
+// grouping in incident tooling and for dedup/rate limiting of error logs.
+// Fingerprint(nil) returns "".
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+	h := fnv.New64a()
+	Walk(err, func(e error) bool {
+		writeFingerprintNode(h, e)
+		return true
+	})
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// writeFingerprintNode writes one node's identity, newline-terminated.
+func writeFingerprintNode(w io.Writer, e error) {
+	n := Inspect(e)
+	arity := len(n.Errors)
+	if n.Cause != nil {
+		arity = 1
+	}
+	_, _ = io.WriteString(w, n.Kind.String()+"|"+string(n.Code)+"|"+strconv.Itoa(arity)+"|")
+	if n.Kind == KindForeign {
+		// The dynamic type separates e.g. *fs.PathError from *net.OpError.
+		_, _ = fmt.Fprintf(w, "%T|", e)
+	}
+	_, _ = io.WriteString(w, msgTemplate(n.Msg))
+	kept := 0
+	for _, fr := range n.Stack.All() {
+		if kept == fingerprintFrames {
+			break
+		}
+		if inMainModule(fr.Function) {
+			_, _ = io.WriteString(w, "|"+fr.Function)
+			kept++
+		}
+	}
+	_, _ = io.WriteString(w, "\n")
+}
+
+// msgTemplate collapses the volatile parts of a message:
+//   - double- and back-quoted spans become "*" (e.g. `open "/tmp/x"` → `open "*"`);
+//   - tokens of letters, digits and "_-." that contain a digit become "#"
+//     ("order 42", "10.0.0.1:5432", "550e8400-e29b-…", "v1.2" → "order #", "#:#", …).
+//
+// Digit-free words are kept, so "user not found" stays distinct from
+// "order not found".
+func msgTemplate(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c == '"' || c == '`' {
+			if j := strings.IndexByte(s[i+1:], c); j >= 0 {
+				b.WriteByte(c)
+				b.WriteByte('*')
+				b.WriteByte(c)
+				i += j + 2
+				continue
+			}
+		}
+		if !isTemplateTokenByte(c) {
+			b.WriteByte(c)
+			i++
+			continue
+		}
+		j, digit := i, false
+		for j < len(s) && isTemplateTokenByte(s[j]) {
+			if s[j] >= '0' && s[j] <= '9' {
+				digit = true
+			}
+			j++
+		}
+		if digit {
+			b.WriteByte('#')
+		} else {
+			b.WriteString(s[i:j])
+		}
+		i = j
+	}
+	return b.String()
+}
+
+// isTemplateTokenByte reports whether c belongs to a msgTemplate token.
+// Non-ASCII bytes count as letters so multi-byte words stay intact.
+func isTemplateTokenByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '_' || c == '-' || c == '.' || c >= 0x80
+}
+
+// inMainModule reports whether fn belongs to the main module (or package
+// main). Without build info it falls back to "not the standard library".
+func inMainModule(fn string) bool {
+	pkg := funcPackage(fn)
+	if pkg == "" {
+		return false
+	}
+	if pkg == "main" {
+		return true
+	}
+	if mod := mainModule(); mod != "" {
+		return pkg == mod || strings.HasPrefix(pkg, mod+"/")
+	}
+	first, _, _ := strings.Cut(pkg, "/")
+	return strings.Contains(first, ".")
+}
@@ -0,0 +1,91 @@
+// fingerprint_test.go — verification of stable error fingerprints.
+package xgxerror
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+//go:noinline
+func fingerprintSiteA(id int) error {
+	return Internal(fmt.Errorf("query order %d on 10.0.0.%d:5432", id, id))
+}
+
+//go:noinline
+func fingerprintSiteB(id int) error {
+	return Internal(fmt.Errorf("query order %d on 10.0.0.%d:5432", id, id))
+}
+
+func TestFingerprint_StableAcrossOccurrences(t *testing.T) {
+	t.Parallel()
+
+	a1 := Wrap(fingerprintSiteA(1), "", "request_id", "r-1")
+	a2 := Wrap(fingerprintSiteA(982), "", "request_id", "r-2")
+	if Fingerprint(a1) != Fingerprint(a2) {
+		t.Fatalf("ids and fields must not change the fingerprint:\n%v\n%v", a1, a2)
+	}
+	if fp := Fingerprint(a1); len(fp) != 16 {
+		t.Fatalf("want 16 hex digits, got %q", fp)
+	}
+	if Fingerprint(nil) != "" {
+		t.Fatalf("nil should have no fingerprint")
+	}
+}
+
+func TestFingerprint_DistinguishesSitesCodesAndShape(t *testing.T) {
+	t.Parallel()
+
+	base := fingerprintSiteA(1)
+	distinct := map[string]error{
+		"site":     fingerprintSiteB(1),
+		"code":     Recode(base, CodeUnavailable),
+		"message":  NotFound("order", 1),
+		"join":     Join(base, NotFound("user", 1)),
+		"foreign":  fmt.Errorf("repo: %w", base),
+		"template": Internal(errors.New("query user 1")),
+	}
+	seen := map[string]string{Fingerprint(base): "base"}
+	for name, err := range distinct {
+		fp := Fingerprint(err)
+		if prev, dup := seen[fp]; dup {
+			t.Fatalf("%s collides with %s (%s)", name, prev, fp)
+		}
+		seen[fp] = name
+	}
+	if Fingerprint(NotFound("user", 1)) != Fingerprint(NotFound("user", "u-77")) {
+		t.Fatalf("ids live in context, not the fingerprint")
+	}
+	if Fingerprint(NotFound("user", 1)) == Fingerprint(NotFound("order", 1)) {
+		t.Fatalf("entity names are part of the message template")
+	}
+}
+
+func TestMsgTemplate(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"user not found":                               "user not found",
+		"query order 42 on 10.0.0.1:5432":              "query order # on #:#",
+		`open "/tmp/x-81/data.db": no such file`:       `open "*": no such file`,
+		"id 550e8400-e29b-41d4-a716-446655440000 gone": "id # gone",
+		"tenant ünïcode v2 failed":                     "tenant ünïcode # failed",
+		`unterminated "quote 7`:                        `unterminated "quote #`,
+	}
+	for in, want := range cases {
+		if got := msgTemplate(in); got != want {
+			t.Fatalf("msgTemplate(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestInMainModule(t *testing.T) {
+	t.Parallel()
+
+	if !inMainModule("github.com/tuliorib/xgx-error.TestInMainModule") || !inMainModule("main.main") {
+		t.Fatalf("module and main frames should count")
+	}
+	if inMainModule("runtime.goexit") || inMainModule("github.com/other/lib.Do") || inMainModule("nodot") {
+		t.Fatalf("runtime and foreign module frames should not count")
+	}
+}
@@ -3,13 +3,19 @@
 // Behavior:
 //
 //	%s, %v   → concise string (Error()).
+//	%#v      → unwrap graph as an indented tree (see Tree).
 //	%+v      → verbose, structured multi-line format:
 //	             code=<code> msg="<message>"
-//	             ctx: key1=val1 key2=val2 ...   // omitted if no printable fields
+//	             ctx: key1=val1 key2=val2 ...   // omitted if no printable fields; sensitive values redacted
+//	             details:                 // omitted if none; oldest first, "<type> <%+v>"
+//	               *pkg.QuotaFailure &{Limit:10}
 //	             cause: <recursively formatted with %+v> // omitted if cause == nil
 //	             stack:
 //	               funcA file.go:123
 //	               funcB other.go:45
+//	             trace:                   // return trace, oldest first; only if recorded
+//	               caller1 file.go:10
+//	               caller2 other.go:20
 //
 // Rationale:
 //   - Keep core free of logging/HTTP/JSON policy; only fmt formatting.
@@ -29,10 +35,13 @@ func formatConcise(w io.Writer, e error) {
 }
 
 // formatVerbose writes a structured multi-line representation.
-// If stk is nil/empty, the stack section is omitted.
+// If stk is empty (or fully filtered by its StackPolicy), the stack section is
+// omitted; frames resolve lazily here.
 // If cause is non-nil, it is formatted with %+v to recurse verbosely.
 // If, after filtering, there are no printable context fields, the ctx: line is omitted.
-func formatVerbose(w io.Writer, code Code, msg string, ctx fields, cause error, stk Stack) {
+// If there are no details, the details: section is omitted.
+// If trace is empty (return traces off), the trace section is omitted.
+func formatVerbose(w io.Writer, code Code, msg string, ctx fields, details []any, cause error, stk Stack, trace []uintptr) {
 	// Header: code + msg
 	if code != "" {
 		_, _ = fmt.Fprintf(w, "code=%s ", code)
@@ -53,12 +62,21 @@ func formatVerbose(w io.Writer, code Code, msg string, ctx fields, cause error,
 		_, _ = io.WriteString(w, "\nctx:")
 		for _, f := range ctx {
 			// Print key only if non-empty; values are %v for generality.
+			// Sensitive values render as RedactedText.
 			if f.Key != "" {
-				_, _ = fmt.Fprintf(w, " %s=%v", f.Key, f.Val)
+				_, _ = fmt.Fprintf(w, " %s=%v", f.Key, f.Redacted().Val)
 			}
 		}
 	}
 
+	// --- Details (oldest first; Secret values render redacted) ---
+	if len(details) > 0 {
+		_, _ = io.WriteString(w, "\ndetails:")
+		for _, d := range details {
+			_, _ = fmt.Fprintf(w, "\n  %T %+v", d, d)
+		}
+	}
+
 	// --- Cause ---
 	// Suppress cause section when cause == nil.
 	if cause != nil {
@@ -68,11 +86,28 @@ func formatVerbose(w io.Writer, code Code, msg string, ctx fields, cause error,
 	}
 
 	// --- Stack frames (most recent first) ---
-	if len(stk) > 0 {
+	// Frames shared with the nearest stack in the cause chain (already
+	// rendered above) are elided Java-style.
+	if frames := stk.Filtered(); len(frames) > 0 {
+		common := 0
+		if cs := causeStack(cause); !cs.Empty() {
+			common = commonFrameSuffix(frames, cs.Filtered())
+		}
 		_, _ = io.WriteString(w, "\nstack:")
-		for _, fr := range stk {
+		for _, fr := range frames[:len(frames)-common] {
 			// Function names are fully-qualified (pkg.Func / recv.method).
-			// File paths come from runtime; we print as-is for accuracy.
+			// File paths come from runtime, rewritten per the StackPolicy.
+			_, _ = fmt.Fprintf(w, "\n  %s %s:%d", fr.Function, fr.File, fr.Line)
+		}
+		if common > 0 {
+			_, _ = fmt.Fprintf(w, "\n  ... %d frames in common with cause", common)
+		}
+	}
+
+	// --- Return trace (oldest wrap first) ---
+	if frames := traceFrames(trace); len(frames) > 0 {
+		_, _ = io.WriteString(w, "\ntrace:")
+		for _, fr := range frames {
 			_, _ = fmt.Fprintf(w, "\n  %s %s:%d", fr.Function, fr.File, fr.Line)
 		}
 	}
@@ -85,8 +120,12 @@ func formatVerbose(w io.Writer, code Code, msg string, ctx fields, cause error,
 func (e *failureErr) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
+		if s.Flag('#') {
+			_, _ = io.WriteString(s, Tree(e))
+			return
+		}
 		if s.Flag('+') {
-			formatVerbose(s, e.code, e.msg, e.ctx, e.cause, e.stk)
+			formatVerbose(s, e.code, e.msg, e.ctx, e.details, e.cause, e.stk, e.trace)
 			return
 		}
 		formatConcise(s, e)
@@ -106,9 +145,13 @@ func (e *failureErr) Format(s fmt.State, verb rune) {
 func (e *defectErr) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
+		if s.Flag('#') {
+			_, _ = io.WriteString(s, Tree(e))
+			return
+		}
 		if s.Flag('+') {
 			// Verbose: print code once and avoid duplicating "defect:" in msg.
-			formatVerbose(s, CodeDefect, e.plainMsgOrCause(), e.ctx, e.cause, e.stk)
+			formatVerbose(s, CodeDefect, e.plainMsgOrCause(), e.ctx, e.details, e.cause, e.stk, e.trace)
 			return
 		}
 		// Concise: delegate to Error(), which includes "defect: ..."
@@ -141,9 +184,13 @@ func (e *defectErr) plainMsgOrCause() string {
 func (e *interruptErr) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
+		if s.Flag('#') {
+			_, _ = io.WriteString(s, Tree(e))
+			return
+		}
 		if s.Flag('+') {
 			// Interrupts print code + msg + ctx + cause (no stack).
-			formatVerbose(s, CodeInterrupt, e.msg, e.ctx, e.cause, nil)
+			formatVerbose(s, CodeInterrupt, e.msg, e.ctx, e.details, e.cause, Stack{}, e.trace)
 			return
 		}
 		formatConcise(s, e)
@@ -193,3 +193,32 @@ func TestPercentPlusV_MinimalDefectSections(t *testing.T) {
 	// No duplication of the structured header on separate adjacent lines.
 	notContains(t, out, "code=defect code=defect")
 }
+
+//go:noinline
+func nestedInternal() error {
+	inner := Defect(errors.New("nil map"))
+	return Internal(inner)
+}
+
+func TestPercentPlusV_ElidesFramesInCommonWithCause(t *testing.T) {
+	t.Parallel()
+
+	err := nestedInternal()
+	out := fmt.Sprintf("%+v", err)
+	containsAll(t, out, "frames in common with cause")
+
+	// The shared caller frame (this test) is printed once, by the cause.
+	name := "TestPercentPlusV_ElidesFramesInCommonWithCause"
+	if n := strings.Count(out, name); n != 1 {
+		t.Fatalf("shared frame printed %d times:\n%s", n, out)
+	}
+	outer := Inspect(err)
+	common := outer.Stack.CommonSuffix(outer.CauseStack())
+	if common == 0 || !strings.Contains(out, "... "+strconv.Itoa(common)+" frames in common with cause") {
+		t.Fatalf("elision count mismatch (common=%d):\n%s", common, out)
+	}
+
+	// Foreign wrappers hide the inner stack from %+v, so nothing is elided.
+	hidden := Internal(fmt.Errorf("wrap: %w", Defect(errors.New("x"))))
+	notContains(t, fmt.Sprintf("%+v", hidden), "in common with cause")
+}
@@ -0,0 +1,127 @@
+// group.go — concurrent fan-out that joins every failure.
+//
+// Group is an errgroup-style helper built on Join:
+//   - Go/GoNamed start tasks that share one cancelable context.
+//   - Wait returns Join of EVERY task error (not just the first), ordered by
+//     task start order, or nil when all tasks succeed.
+//   - Each error carries a "task" field: the 0-based start index for Go, the
+//     name for GoNamed.
+//   - Panics inside tasks are recovered into Defects (see recover.go).
+//   - The shared context is canceled on the first defect, or on the first
+//     error matching the CancelOn predicate; context.Cause reports that error.
+//   - Join/Append are not safe for concurrent use; Group serializes collection.
+//
+// The zero Group is usable and runs tasks under context.Background().
+package xgxerror
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// GroupOption configures a Group.
+type GroupOption func(*Group)
+
+// CancelOn also cancels the group's context on the first error for which
+// pred returns true. Defects always cancel.
+func CancelOn(pred func(error) bool) GroupOption {
+	return func(g *Group) { g.cancelOn = pred }
+}
+
+// Group runs tasks concurrently and collects all of their errors.
+type Group struct {
+	initOnce sync.Once
+	ctx      context.Context
+	cancel   context.CancelCauseFunc
+	cancelOn func(error) bool
+
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	next int
+	errs []taskErr
+}
+
+type taskErr struct {
+	idx int
+	err error
+}
+
+// NewGroup returns a Group whose tasks run under a context derived from ctx.
+func NewGroup(ctx context.Context, opts ...GroupOption) *Group {
+	g := &Group{}
+	for _, o := range opts {
+		o(g)
+	}
+	g.initOnce.Do(func() { g.ctx, g.cancel = context.WithCancelCause(ctx) })
+	return g
+}
+
+// Go starts fn in a new goroutine; its error is tagged task=<start index>.
+func (g *Group) Go(fn func(ctx context.Context) error) {
+	g.spawn(nil, fn)
+}
+
+// GoNamed starts fn in a new goroutine; its error is tagged task=name.
+func (g *Group) GoNamed(name string, fn func(ctx context.Context) error) {
+	g.spawn(name, fn)
+}
+
+// Wait blocks until all tasks return, cancels the group's context and returns
+// the joined task errors in start order (nil if none failed).
+func (g *Group) Wait() error {
+	g.init()
+	g.wg.Wait()
+	g.cancel(nil)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	sort.Slice(g.errs, func(i, j int) bool { return g.errs[i].idx < g.errs[j].idx })
+	out := make([]error, len(g.errs))
+	for i, te := range g.errs {
+		out[i] = te.err
+	}
+	return Join(out...)
+}
+
+func (g *Group) init() {
+	g.initOnce.Do(func() { g.ctx, g.cancel = context.WithCancelCause(context.Background()) })
+}
+
+func (g *Group) spawn(name any, fn func(ctx context.Context) error) {
+	g.init()
+	g.mu.Lock()
+	idx := g.next
+	g.next++
+	g.mu.Unlock()
+	if name == nil {
+		name = idx
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		err := Safe(func() error { return fn(g.ctx) })
+		if err == nil {
+			return
+		}
+		err = withTask(err, name)
+
+		g.mu.Lock()
+		g.errs = append(g.errs, taskErr{idx: idx, err: err})
+		g.mu.Unlock()
+
+		if IsDefect(err) || (g.cancelOn != nil && g.cancelOn(err)) {
+			g.cancel(err)
+		}
+	}()
+}
+
+// withTask attaches the task field. Foreign errors are adopted (see Adopt),
+// so neither Error() nor CodeOf changes.
+func withTask(err error, task any) error {
+	if xe, ok := err.(Error); ok {
+		return xe.With("task", task)
+	}
+	return Adopt(err, "task", task)
+}
@@ -0,0 +1,177 @@
+// group_test.go — verification of the concurrent error group.
+package xgxerror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tasksOf returns the "task" field of every tagged node in err, in DFS order.
+func tasksOf(err error) []any {
+	var out []any
+	Walk(err, func(e error) bool {
+		if xe, ok := e.(Error); ok {
+			if v, ok := xe.Context()["task"]; ok {
+				out = append(out, v)
+			}
+		}
+		return true
+	})
+	return out
+}
+
+func TestGroup_JoinsEveryFailureInStartOrder(t *testing.T) {
+	t.Parallel()
+
+	g := NewGroup(context.Background())
+	release := make(chan struct{})
+	g.Go(func(context.Context) error { <-release; return NotFound("user", 1) })
+	g.Go(func(context.Context) error { return nil })
+	g.Go(func(context.Context) error { close(release); return errors.New("plain") })
+	g.GoNamed("billing", func(context.Context) error { return Unavailable("billing") })
+
+	err := g.Wait()
+	if got := fmt.Sprint(tasksOf(err)); got != "[0 2 billing]" {
+		t.Fatalf("tasks: %v (err=%v)", got, err)
+	}
+	if !HasCode(err, CodeNotFound) || !HasCode(err, CodeUnavailable) {
+		t.Fatalf("failures lost: %v", err)
+	}
+	if IsDefect(err) {
+		t.Fatalf("no defect expected")
+	}
+}
+
+func TestGroup_AllSucceedReturnsNil(t *testing.T) {
+	t.Parallel()
+
+	var g Group // zero value is usable
+	var n atomic.Int32
+	for range 10 {
+		g.Go(func(ctx context.Context) error {
+			if ctx == nil {
+				return errors.New("nil ctx")
+			}
+			n.Add(1)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil || n.Load() != 10 {
+		t.Fatalf("want nil after 10 tasks, got %v (%d)", err, n.Load())
+	}
+}
+
+func TestGroup_PanicBecomesDefectAndCancels(t *testing.T) {
+	t.Parallel()
+
+	g := NewGroup(context.Background())
+	g.Go(func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return Interrupt("sibling canceled")
+		case <-time.After(5 * time.Second):
+			return errors.New("not canceled")
+		}
+	})
+	g.GoNamed("crashy", func(context.Context) error { panic("boom") })
+
+	err := g.Wait()
+	if !IsDefect(err) || !IsInterrupt(err) {
+		t.Fatalf("want defect and interrupted sibling, got %v", err)
+	}
+	if got := fmt.Sprint(tasksOf(err)); got != "[0 crashy]" {
+		t.Fatalf("tasks: %v", got)
+	}
+}
+
+func TestGroup_CancelOnPredicateAndCause(t *testing.T) {
+	t.Parallel()
+
+	parent := context.Background()
+	g := NewGroup(parent, CancelOn(func(err error) bool { return HasCode(err, CodeUnauthorized) }))
+	var cause error
+	done := make(chan struct{})
+	g.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		cause = context.Cause(ctx)
+		close(done)
+		return nil
+	})
+	g.Go(func(context.Context) error { return Unauthorized("token expired") })
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("predicate did not cancel the group")
+	}
+	_ = g.Wait()
+	if !HasCode(cause, CodeUnauthorized) || fmt.Sprint(tasksOf(cause)) != "[1]" {
+		t.Fatalf("context cause should be the tagged error, got %v", cause)
+	}
+}
+
+func TestGroup_FailureDominatesCanceledSiblings(t *testing.T) {
+	t.Parallel()
+
+	g := NewGroup(context.Background(), CancelOn(func(err error) bool { return HasCode(err, CodeInternal) }))
+	g.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		return Interrupt("sibling canceled")
+	})
+	g.Go(func(context.Context) error { return Internal(errors.New("db")) })
+	err := g.Wait()
+	if !IsInterrupt(err) || DominantCode(err, nil) != CodeInternal {
+		t.Fatalf("dominant code of a canceling failure: %q (%v)", DominantCode(err, nil), err)
+	}
+}
+
+func TestGroup_ForeignErrorsKeepCodeAndCause(t *testing.T) {
+	t.Parallel()
+
+	sentinel := errors.New("io")
+	g := NewGroup(context.Background())
+	g.Go(func(context.Context) error { return fmt.Errorf("repo: %w", Conflict("dup")) })
+	g.Go(func(context.Context) error { return sentinel })
+	err := g.Wait()
+
+	var m interface{ Unwrap() []error }
+	if !errors.As(err, &m) || len(m.Unwrap()) != 2 {
+		t.Fatalf("want 2 joined errors, got %v", err)
+	}
+	kids := m.Unwrap()
+	if CodeOf(kids[0]) != CodeConflict || CodeOf(kids[1]) != "" || !errors.Is(kids[1], sentinel) {
+		t.Fatalf("foreign tagging changed classification: %v", err)
+	}
+	if kids[0].Error() != "repo: conflict: dup" || kids[1].Error() != "io" {
+		t.Fatalf("foreign tagging changed the text: %q, %q", kids[0], kids[1])
+	}
+}
+
+func TestGroup_TaskTagsKeepInterruptsAndJoins(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	g := NewGroup(ctx)
+	g.Go(func(ctx context.Context) error { return ctx.Err() })
+	err := g.Wait()
+	if !IsInterrupt(err) || DominantCode(err, nil) != CodeInterrupt || HasCode(err, CodeInternal) || CodeOf(err) != "" {
+		t.Fatalf("canceled task: interrupt=%v dominant=%q code=%q", IsInterrupt(err), DominantCode(err, nil), CodeOf(err))
+	}
+
+	g = NewGroup(context.Background())
+	g.GoNamed("batch", func(context.Context) error {
+		return Join(Invalid("email", "format"), Internal(errors.New("db")))
+	})
+	err = g.Wait()
+	if DominantCode(err, nil) != CodeInternal || !HasCode(err, CodeInvalid) {
+		t.Fatalf("joined task: dominant=%q", DominantCode(err, nil))
+	}
+	if got := tasksOf(err); len(got) != 1 || got[0] != "batch" {
+		t.Fatalf("joined task: task fields %v", got)
+	}
+}
@@ -0,0 +1,226 @@
+// client.go — decoding 4xx/5xx HTTP responses back into xgx errors.
+//
+// Mapping (client side):
+//   - problem+json bodies: "code" member (else the "type" suffix after
+//     TypeBase, else the status) → Code; "detail" (else "title") → message;
+//     extension members → context fields in key order.
+//   - other bodies: status → the first registered code with that HTTPStatus
+//     (404 → not_found, 429 → too_many_requests, 503 → unavailable, ...);
+//     unknown 5xx → internal (502 → unavailable), unknown 4xx → bad_request.
+//   - every error carries FieldStatus; a Retry-After header (seconds or
+//     HTTP-date) becomes FieldRetryAfter.
+//
+// Do returns the failed response together with its error; Transport trades
+// the response away to fit into an unmodified http.Client.
+//
+// A remote defect or interrupt is a failure of the dependency, not of this
+// process: such codes decode as CodeInternal with the original kept under
+// "remote_code", so IsDefect/IsInterrupt stay truthful about local state.
+package httpx
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	xgxerror "github.com/tuliorib/xgx-error"
+)
+
+// FieldStatus carries the HTTP status of a decoded response.
+var FieldStatus = xgxerror.FieldOf[int]("http_status")
+
+// FieldRetryAfter carries the server's Retry-After hint. WriteError emits it
+// as a Retry-After header; FromResponse reads it back.
+var FieldRetryAfter = xgxerror.FieldOf[time.Duration]("retry_after")
+
+// maxProblemBody bounds how much of a JSON body is read for decoding.
+const maxProblemBody = 64 << 10
+
+// FromResponse converts a 4xx/5xx response into an Error using DefaultOptions.
+// It returns nil for nil responses and any status below 400.
+func FromResponse(resp *http.Response) error {
+	return DefaultOptions.FromResponse(resp)
+}
+
+// FromResponse converts a 4xx/5xx response into an Error. It returns nil for
+// nil responses and any status below 400: 1xx, 2xx and 3xx (redirects, 304
+// Not Modified) are not failures.
+//
+// The body is only consumed for JSON content types, and what is read is put
+// back, so callers may still read resp.Body afterwards. Closing it remains
+// the caller's job.
+func (o *Options) FromResponse(resp *http.Response) error {
+	if resp == nil || resp.StatusCode < 400 {
+		return nil
+	}
+	status := resp.StatusCode
+	var (
+		code xgxerror.Code
+		msg  string
+		ctx  []xgxerror.Field
+	)
+	if p := readProblem(resp); p != nil {
+		code = p.Code
+		if code == "" && strings.HasPrefix(p.Type, o.typeBase()) {
+			code = xgxerror.Code(strings.TrimPrefix(p.Type, o.typeBase()))
+		}
+		msg = p.Detail
+		if msg == "" {
+			msg = p.Title
+		}
+		keys := make([]string, 0, len(p.Extensions))
+		for k := range p.Extensions {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			ctx = append(ctx, xgxerror.Field{Key: k, Val: p.Extensions[k]})
+		}
+	}
+	if code == "" {
+		code = o.codeForStatus(status)
+	}
+	if code.IsA(xgxerror.CodeDefect) || code.IsA(xgxerror.CodeInterrupt) {
+		ctx = append(ctx, xgxerror.Field{Key: "remote_code", Val: string(code)})
+		code = xgxerror.CodeInternal
+	}
+	if msg == "" {
+		msg = strings.ToLower(statusText(status))
+	}
+	ctx = append(ctx, xgxerror.Field{Key: FieldStatus.Key(), Val: status})
+	if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		ctx = append(ctx, xgxerror.Field{Key: FieldRetryAfter.Key(), Val: d})
+	}
+	return xgxerror.Restore(xgxerror.Node{Kind: xgxerror.KindFailure, Code: code, Msg: msg, Fields: ctx})
+}
+
+// Transport is an http.RoundTripper that turns 4xx/5xx responses into errors
+// via FromResponse. The response body is closed in that case; http.Client
+// surfaces the error wrapped in *url.Error, which predicates see through.
+// Other responses pass through unchanged, so http.Client still follows
+// redirects and conditional requests see their 304s.
+//
+// This deliberately breaks the http.RoundTripper contract, which reserves
+// errors for requests that got no response: callers lose the failed
+// response's headers and body, and middleware layered above Transport
+// sees a transport error instead of a status. Use Do when the response is
+// still needed.
+type Transport struct {
+	// Base performs the request; nil selects http.DefaultTransport.
+	Base http.RoundTripper
+	// Options configures decoding; nil selects DefaultOptions.
+	Options *Options
+}
+
+// RoundTrip executes req and decodes failed responses.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	o := t.Options
+	if o == nil {
+		o = DefaultOptions
+	}
+	if xerr := o.FromResponse(resp); xerr != nil {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxProblemBody))
+		_ = resp.Body.Close()
+		return nil, xerr
+	}
+	return resp, nil
+}
+
+// Do sends req with client (nil selects http.DefaultClient) and decodes a
+// 4xx/5xx response using DefaultOptions. See Options.Do.
+func Do(client *http.Client, req *http.Request) (*http.Response, error) {
+	return DefaultOptions.Do(client, req)
+}
+
+// Do sends req with client (nil selects http.DefaultClient) and, unlike
+// Transport, returns a failed response together with its decoded error. The
+// body is left readable (see FromResponse); closing it remains the caller's
+// job whenever the response is non-nil.
+func (o *Options) Do(client *http.Client, req *http.Request) (*http.Response, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return resp, err
+	}
+	return resp, o.FromResponse(resp)
+}
+
+// readProblem decodes a JSON body as a Problem, restoring what it read.
+func readProblem(resp *http.Response) *Problem {
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return nil
+	}
+	mt, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mt != ContentType && mt != "application/json" {
+		return nil
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxProblemBody))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+
+	var p Problem
+	if json.Unmarshal(data, &p) != nil {
+		return nil
+	}
+	return &p
+}
+
+// codeForStatus maps a status to the first registered code declaring it.
+func (o *Options) codeForStatus(status int) xgxerror.Code {
+	for _, info := range o.registry().List() {
+		if info.HTTPStatus != status {
+			continue
+		}
+		if info.Code.IsA(xgxerror.CodeDefect) || info.Code.IsA(xgxerror.CodeInterrupt) {
+			continue
+		}
+		return info.Code
+	}
+	switch {
+	case status == http.StatusBadGateway:
+		return xgxerror.CodeUnavailable
+	case status >= 400 && status < 500:
+		return xgxerror.CodeBadRequest
+	}
+	return xgxerror.CodeInternal
+}
+
+// parseRetryAfter reads delay-seconds or an HTTP-date relative to now.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
@@ -0,0 +1,253 @@
+// client_test.go — verification of client-side response decoding.
+package httpx
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	xgxerror "github.com/tuliorib/xgx-error"
+)
+
+func response(status int, contentType, body string, hdr ...string) *http.Response {
+	h := http.Header{}
+	if contentType != "" {
+		h.Set("Content-Type", contentType)
+	}
+	for i := 0; i+1 < len(hdr); i += 2 {
+		h.Set(hdr[i], hdr[i+1])
+	}
+	return &http.Response{StatusCode: status, Header: h, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func asError(t *testing.T, err error) xgxerror.Error {
+	t.Helper()
+	var xe xgxerror.Error
+	if !errors.As(err, &xe) {
+		t.Fatalf("expected a native Error, got %T: %v", err, err)
+	}
+	return xe
+}
+
+func TestFromResponse_SuccessIsNil(t *testing.T) {
+	t.Parallel()
+
+	for _, status := range []int{101, 204, 301, 302, 304, 307} {
+		if FromResponse(response(status, "", "")) != nil {
+			t.Fatalf("status %d must decode to nil", status)
+		}
+	}
+	if FromResponse(nil) != nil {
+		t.Fatalf("nil response must decode to nil")
+	}
+}
+
+func TestFromResponse_ProblemRoundTripsThroughServer(t *testing.T) {
+	t.Parallel()
+
+	o := &Options{Fields: []string{"entity", "id"}}
+	srv := httptest.NewServer(o.Handler(failing(xgxerror.NotFound("user", 42))))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/users/42")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+
+	xe := asError(t, o.FromResponse(resp))
+	if xe.CodeVal() != xgxerror.CodeNotFound || xgxerror.Inspect(xe).Msg != "user not found" {
+		t.Fatalf("code/msg lost: %v", xe)
+	}
+	ctx := xe.Context()
+	if ctx["entity"] != "user" || ctx["id"] == nil {
+		t.Fatalf("extension fields lost: %v", ctx)
+	}
+	if s, _ := FieldStatus.Get(xe); s != 404 {
+		t.Fatalf("http_status: %d", s)
+	}
+	// The body stays readable for callers.
+	if b, _ := io.ReadAll(resp.Body); !strings.Contains(string(b), `"not_found"`) {
+		t.Fatalf("body not restored: %q", b)
+	}
+}
+
+func TestFromResponse_CodeFromTypeSuffix(t *testing.T) {
+	t.Parallel()
+
+	body := `{"type":"urn:xgx-error:conflict.version","status":409,"title":"Conflict"}`
+	xe := asError(t, FromResponse(response(409, ContentType, body)))
+	if xe.CodeVal() != "conflict.version" || xgxerror.Inspect(xe).Msg != "Conflict" {
+		t.Fatalf("type suffix not used: %v", xe)
+	}
+	if !xgxerror.HasCode(xe, xgxerror.CodeConflict) {
+		t.Fatalf("decoded code should keep its hierarchy")
+	}
+}
+
+func TestFromResponse_StatusMappingForNonJSON(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		status    int
+		code      xgxerror.Code
+		retryable bool
+	}{
+		{404, xgxerror.CodeNotFound, false},
+		{429, xgxerror.CodeTooManyRequests, true},
+		{503, xgxerror.CodeUnavailable, true},
+		{504, xgxerror.CodeTimeout, true},
+		{502, xgxerror.CodeUnavailable, true},
+		{500, xgxerror.CodeInternal, false},
+		{405, xgxerror.CodeBadRequest, false},
+	}
+	for _, tc := range cases {
+		err := FromResponse(response(tc.status, "text/html", "<html>oops</html>"))
+		xe := asError(t, err)
+		if xe.CodeVal() != tc.code {
+			t.Fatalf("%d: want %q got %q", tc.status, tc.code, xe.CodeVal())
+		}
+		if xgxerror.IsRetryable(err) != tc.retryable {
+			t.Fatalf("%d: IsRetryable=%v", tc.status, !tc.retryable)
+		}
+		if strings.Contains(xe.Error(), "oops") {
+			t.Fatalf("non-JSON bodies must not become messages: %q", xe.Error())
+		}
+	}
+}
+
+func TestFromResponse_RetryAfter(t *testing.T) {
+	t.Parallel()
+
+	xe := asError(t, FromResponse(response(429, "", "", "Retry-After", "7")))
+	if d, ok := FieldRetryAfter.Get(xe); !ok || d != 7*time.Second {
+		t.Fatalf("retry_after seconds: %v %v", d, ok)
+	}
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	if d, ok := parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now); !ok || d != 90*time.Second {
+		t.Fatalf("retry_after HTTP-date: %v %v", d, ok)
+	}
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Fatalf("garbage Retry-After should be ignored")
+	}
+}
+
+func TestFromResponse_RemoteDefectAndInterruptAreNotLocal(t *testing.T) {
+	t.Parallel()
+
+	for _, code := range []string{"defect", "interrupt"} {
+		body := `{"type":"urn:xgx-error:` + code + `","status":500,"code":"` + code + `"}`
+		err := FromResponse(response(500, ContentType, body))
+		if xgxerror.IsDefect(err) || xgxerror.IsInterrupt(err) {
+			t.Fatalf("remote %s leaked into local classification", code)
+		}
+		if xe := asError(t, err); xe.CodeVal() != xgxerror.CodeInternal || xe.Context()["remote_code"] != code {
+			t.Fatalf("remote %s: code=%q ctx=%v", code, xe.CodeVal(), xe.Context())
+		}
+	}
+}
+
+func TestTransport_ReturnsErrorsThroughClient(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(failing(xgxerror.TooManyRequests("api").With(FieldRetryAfter.Key(), 3*time.Second)))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &Transport{}}
+	_, err := client.Get(srv.URL)
+	var ue *url.Error
+	if !errors.As(err, &ue) {
+		t.Fatalf("expected *url.Error, got %T", err)
+	}
+	if !xgxerror.HasCode(err, xgxerror.CodeTooManyRequests) || !xgxerror.IsRetryable(err) {
+		t.Fatalf("classification lost across the hop: %v", err)
+	}
+	if d, ok := FieldRetryAfter.Get(asError(t, err)); !ok || d != 3*time.Second {
+		t.Fatalf("Retry-After header not round-tripped: %v %v", d, ok)
+	}
+
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer ok.Close()
+	resp, err := client.Get(ok.URL)
+	if err != nil {
+		t.Fatalf("2xx should pass through: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestDo_KeepsFailedResponse(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(failing(xgxerror.TooManyRequests("api").With(FieldRetryAfter.Key(), 3*time.Second)))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, err := Do(nil, req)
+	if resp == nil {
+		t.Fatalf("failed response dropped: %v", err)
+	}
+	defer resp.Body.Close()
+	if !xgxerror.HasCode(err, xgxerror.CodeTooManyRequests) || resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("status %d, err %v", resp.StatusCode, err)
+	}
+	if body, _ := io.ReadAll(resp.Body); !strings.Contains(string(body), `"code":"too_many_requests"`) {
+		t.Fatalf("body not restored: %s", body)
+	}
+	if resp.Header.Get("Retry-After") != "3" {
+		t.Fatalf("headers lost: %v", resp.Header)
+	}
+
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer ok.Close()
+	req, _ = http.NewRequest(http.MethodGet, ok.URL, nil)
+	resp, err = Do(&http.Client{}, req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("2xx: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestTransport_RedirectsAndNotModifiedPassThrough(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusFound)
+	})
+	mux.HandleFunc("/new", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "moved here")
+	})
+	mux.HandleFunc("/cached", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	client := &http.Client{Transport: &Transport{}}
+	resp, err := client.Get(srv.URL + "/old")
+	if err != nil {
+		t.Fatalf("redirect should be followed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "moved here" {
+		t.Fatalf("redirect target: %d %q", resp.StatusCode, body)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/cached", nil)
+	req.Header.Set("If-None-Match", `"v1"`)
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("304 should pass through: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotModified {
+		t.Fatalf("status: %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,338 @@
+// problem.go — RFC 9457 Problem Details documents for xgx errors.
+//
+// Package httpx converts any error into an RFC 9457 "problem+json" document
+// and writes it as an HTTP response, so handlers stop hand-rolling the same
+// HasCode switch (and stop disagreeing about interrupts and defects).
+//
+// Mapping (server side):
+//   - code:   defects win, then xgxerror.DominantCode under Options.Precedence
+//     (interrupts and server failures outrank client errors); no code → internal.
+//   - status: Options.Statuses (honoring the dotted code hierarchy), then the
+//     registry's HTTPStatus, then 500.
+//   - type:   Options.TypeBase + code (e.g., "urn:xgx-error:not_found").
+//   - title:  http.StatusText(status) ("Client Closed Request" for 499).
+//   - detail: for client errors (status < 500) the message of the node
+//     carrying the code; otherwise, or if that is empty, the registry's
+//     DefaultMessage (then the title). Server-side messages never leak.
+//   - headers: Content-Type problem+json; Retry-After when the graph carries
+//     FieldRetryAfter (rounded up to whole seconds).
+//   - extension members: "code" always; allow-listed context fields
+//     (Options.Fields) from the whole graph, outermost node first. Sensitive
+//     fields stay redacted because values are read through Context().
+//
+// The reverse direction (responses → errors) lives in client.go.
+package httpx
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	xgxerror "github.com/tuliorib/xgx-error"
+)
+
+// ContentType is the RFC 9457 media type for problem documents.
+const ContentType = "application/problem+json"
+
+// StatusClientClosedRequest is the de-facto status for canceled requests.
+const StatusClientClosedRequest = 499
+
+// DefaultTypeBase prefixes codes to build the problem "type" URI.
+const DefaultTypeBase = "urn:xgx-error:"
+
+// Problem is an RFC 9457 Problem Details document.
+//
+// Extensions holds additional members; it cannot override the standard
+// members. Code is emitted as the "code" extension member.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Code       xgxerror.Code
+	Extensions map[string]any
+}
+
+// standardMembers are the RFC 9457 members plus "code".
+var standardMembers = map[string]struct{}{
+	"type": {}, "title": {}, "status": {}, "detail": {}, "instance": {}, "code": {},
+}
+
+// MarshalJSON flattens standard and extension members into one object.
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]any, len(p.Extensions)+6)
+	for k, v := range p.Extensions {
+		if _, std := standardMembers[k]; !std {
+			m[k] = v
+		}
+	}
+	m["type"] = p.Type
+	m["status"] = p.Status
+	if p.Title != "" {
+		m["title"] = p.Title
+	}
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+	if p.Code != "" {
+		m["code"] = string(p.Code)
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON reads standard members and collects the rest as Extensions.
+// Numbers in extensions decode as json.Number to avoid float64 surprises.
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*p = Problem{}
+	for k, v := range raw {
+		var err error
+		switch k {
+		case "type":
+			err = json.Unmarshal(v, &p.Type)
+		case "title":
+			err = json.Unmarshal(v, &p.Title)
+		case "status":
+			err = json.Unmarshal(v, &p.Status)
+		case "detail":
+			err = json.Unmarshal(v, &p.Detail)
+		case "instance":
+			err = json.Unmarshal(v, &p.Instance)
+		case "code":
+			var c string
+			err = json.Unmarshal(v, &c)
+			p.Code = xgxerror.Code(c)
+		default:
+			var x any
+			dec := json.NewDecoder(bytes.NewReader(v))
+			dec.UseNumber()
+			err = dec.Decode(&x)
+			if p.Extensions == nil {
+				p.Extensions = make(map[string]any)
+			}
+			p.Extensions[k] = x
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Options configures problem construction. The zero value is usable.
+type Options struct {
+	// Registry supplies HTTPStatus/DefaultMessage; nil selects the default registry.
+	Registry *xgxerror.Registry
+	// Statuses overrides the registry's code → status mapping. Lookups walk
+	// the code hierarchy, so "unavailable" also covers "unavailable.db".
+	Statuses map[xgxerror.Code]int
+	// TypeBase prefixes the code to build "type"; "" selects DefaultTypeBase.
+	TypeBase string
+	// Fields lists context keys exported as extension members.
+	Fields []string
+	// Precedence ranks codes when err joins several failures (see
+	// xgxerror.DominantCode); nil selects xgxerror.DefaultPrecedence.
+	Precedence []xgxerror.Code
+}
+
+// DefaultOptions is used by the package-level helpers.
+var DefaultOptions = &Options{}
+
+// NewProblem builds a problem document for err using DefaultOptions.
+func NewProblem(r *http.Request, err error) *Problem {
+	return DefaultOptions.Problem(r, err)
+}
+
+// Problem builds a problem document for err. r is optional and only used to
+// fill "instance" with the request path. A nil err yields nil.
+func (o *Options) Problem(r *http.Request, err error) *Problem {
+	if err == nil {
+		return nil
+	}
+	code := classify(err, o.Precedence)
+	info, _ := o.registry().Resolve(code)
+	status := o.status(code, info)
+
+	p := &Problem{
+		Type:   o.typeBase() + string(code),
+		Title:  statusText(status),
+		Status: status,
+		Code:   code,
+	}
+	// Server-side messages (DB errors, panic values, ...) stay private; only
+	// client errors echo the message of the node carrying the code.
+	if status < 500 {
+		p.Detail = messageFor(err, code)
+	}
+	if p.Detail == "" {
+		p.Detail = info.DefaultMessage
+	}
+	if p.Detail == "" {
+		p.Detail = p.Title
+	}
+	if r != nil && r.URL != nil {
+		p.Instance = r.URL.Path
+	}
+	if ext := collectFields(err, o.Fields); len(ext) > 0 {
+		p.Extensions = ext
+	}
+	return p
+}
+
+// WriteError writes err as a problem+json response using DefaultOptions.
+// A nil err writes nothing.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	DefaultOptions.WriteError(w, r, err)
+}
+
+// WriteError writes err as a problem+json response. A nil err writes nothing.
+func (o *Options) WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	p := o.Problem(r, err)
+	if p == nil {
+		return
+	}
+	body, mErr := json.Marshal(p)
+	if mErr != nil {
+		// Extension values that cannot be marshaled: drop them, keep the status.
+		p.Extensions = nil
+		body, _ = json.Marshal(p)
+	}
+	h := w.Header()
+	h.Set("Content-Type", ContentType)
+	h.Set("X-Content-Type-Options", "nosniff")
+	if d, ok := retryAfter(err); ok {
+		h.Set("Retry-After", strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10))
+	}
+	w.WriteHeader(p.Status)
+	_, _ = w.Write(body)
+}
+
+// HandlerFunc is an HTTP handler that reports failures by returning an error.
+type HandlerFunc func(http.ResponseWriter, *http.Request) error
+
+// ServeHTTP calls f and writes any returned error with DefaultOptions.
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := f(w, r); err != nil {
+		WriteError(w, r, err)
+	}
+}
+
+// Handler adapts fn into an http.Handler that writes returned errors with o.
+func (o *Options) Handler(fn HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := fn(w, r); err != nil {
+			o.WriteError(w, r, err)
+		}
+	})
+}
+
+func (o *Options) registry() *xgxerror.Registry {
+	if o.Registry != nil {
+		return o.Registry
+	}
+	return xgxerror.DefaultRegistry()
+}
+
+func (o *Options) typeBase() string {
+	if o.TypeBase != "" {
+		return o.TypeBase
+	}
+	return DefaultTypeBase
+}
+
+func (o *Options) status(code xgxerror.Code, info xgxerror.CodeInfo) int {
+	for c := code; c != ""; c = c.Parent() {
+		if s, ok := o.Statuses[c]; ok {
+			return s
+		}
+	}
+	if info.HTTPStatus != 0 {
+		return info.HTTPStatus
+	}
+	return http.StatusInternalServerError
+}
+
+// classify picks the code that drives the response: the dominant code under
+// precedence, so a batch that hit both a validation error and an outage maps
+// to the outage. Only each branch's outermost code competes, so a handler
+// that recodes an outage as not_found gets a 404. Defects always win regardless of precedence, so a bug is
+// never reported as a client error.
+func classify(err error, precedence []xgxerror.Code) xgxerror.Code {
+	if xgxerror.IsDefect(err) {
+		return xgxerror.CodeDefect
+	}
+	if c := xgxerror.DominantCode(err, precedence); c != "" {
+		return c
+	}
+	return xgxerror.CodeInternal
+}
+
+// messageFor returns the raw message of the first node carrying code.
+func messageFor(err error, code xgxerror.Code) string {
+	var msg string
+	xgxerror.Walk(err, func(e error) bool {
+		n := xgxerror.Inspect(e)
+		if n.Kind != xgxerror.KindForeign && n.Kind != xgxerror.KindJoin && n.Code == code {
+			msg = n.Msg
+			return false
+		}
+		return true
+	})
+	return msg
+}
+
+// collectFields gathers allow-listed keys, outermost node first.
+func collectFields(err error, keys []string) map[string]any {
+	if len(keys) == 0 {
+		return nil
+	}
+	out := make(map[string]any, len(keys))
+	xgxerror.Walk(err, func(e error) bool {
+		xe, ok := e.(xgxerror.Error)
+		if !ok {
+			return true
+		}
+		ctx := xe.Context() // redacted copy
+		for _, k := range keys {
+			if _, done := out[k]; done {
+				continue
+			}
+			if v, ok := ctx[k]; ok {
+				out[k] = v
+			}
+		}
+		return len(out) < len(keys)
+	})
+	return out
+}
+
+// retryAfter returns the outermost FieldRetryAfter in the graph.
+func retryAfter(err error) (time.Duration, bool) {
+	var (
+		d     time.Duration
+		found bool
+	)
+	xgxerror.Walk(err, func(e error) bool {
+		if xe, ok := e.(xgxerror.Error); ok {
+			d, found = FieldRetryAfter.Get(xe)
+		}
+		return !found
+	})
+	return d, found && d >= 0
+}
+
+func statusText(status int) string {
+	if status == StatusClientClosedRequest {
+		return "Client Closed Request"
+	}
+	return http.StatusText(status)
+}
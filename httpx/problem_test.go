@@ -0,0 +1,250 @@
+// problem_test.go — verification of problem+json construction and HTTP writing.
+package httpx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	xgxerror "github.com/tuliorib/xgx-error"
+)
+
+func serve(t *testing.T, h http.Handler, path string) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	var body map[string]any
+	if rec.Body.Len() > 0 {
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("invalid JSON body: %v\n%s", err, rec.Body.String())
+		}
+	}
+	return rec, body
+}
+
+func failing(err error) HandlerFunc {
+	return func(http.ResponseWriter, *http.Request) error { return err }
+}
+
+func TestWriteError_StatusMappingForBuiltins(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"not_found", xgxerror.NotFound("user", 1), 404, "not_found"},
+		{"invalid", xgxerror.Invalid("email", "format"), 400, "invalid"},
+		{"unauthorized", xgxerror.Unauthorized("no token"), 401, "unauthorized"},
+		{"forbidden", xgxerror.Forbidden("admin"), 403, "forbidden"},
+		{"conflict", xgxerror.Conflict("dup"), 409, "conflict"},
+		{"too_many", xgxerror.TooManyRequests("api"), 429, "too_many_requests"},
+		{"unavailable", xgxerror.Unavailable("db"), 503, "unavailable"},
+		{"internal", xgxerror.Internal(errors.New("x")), 500, "internal"},
+		{"foreign", errors.New("plain"), 500, "internal"},
+		{"interrupt", xgxerror.Interrupt("client gone"), 499, "interrupt"},
+		{"wrapped_ctx_canceled", fmt.Errorf("query: %w", context.Canceled), 499, "interrupt"},
+		{"defect_wins", xgxerror.Join(xgxerror.Interrupt("x"), xgxerror.Defect(errors.New("bug"))), 500, "defect"},
+		{"hierarchical", xgxerror.Recode(nil, "not_found.user"), 404, "not_found.user"},
+		{"batch_outage_wins", xgxerror.Join(xgxerror.Invalid("email", "format"), xgxerror.Unavailable("db")), 503, "unavailable"},
+		{"tagged_ctx_canceled", xgxerror.Adopt(context.Canceled, "task", 0), 499, "interrupt"},
+		{"tagged_join", xgxerror.Adopt(xgxerror.Join(xgxerror.Invalid("email", "format"), xgxerror.Internal(errors.New("db"))), "task", 1), 500, "internal"},
+		{"recoded_chain", xgxerror.Recode(xgxerror.Internal(xgxerror.Unavailable("db")), xgxerror.CodeNotFound), 404, "not_found"},
+		{"batch_precise_code", xgxerror.Join(xgxerror.NotFound("user", 1), xgxerror.Recode(nil, "conflict.version")), 409, "conflict.version"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec, body := serve(t, failing(tc.err), "/x")
+			if rec.Code != tc.status {
+				t.Fatalf("status: want %d got %d", tc.status, rec.Code)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+				t.Fatalf("Content-Type: %q", ct)
+			}
+			if body["code"] != tc.code || body["type"] != DefaultTypeBase+tc.code {
+				t.Fatalf("code/type: %v", body)
+			}
+			if body["status"] != float64(tc.status) || body["title"] == "" {
+				t.Fatalf("status/title members: %v", body)
+			}
+		})
+	}
+}
+
+func TestOptions_PrecedenceOverridesDefault(t *testing.T) {
+	t.Parallel()
+
+	err := xgxerror.Join(xgxerror.Invalid("email", "format"), xgxerror.Unavailable("db"))
+	o := &Options{Precedence: []xgxerror.Code{xgxerror.CodeInvalid, xgxerror.CodeUnavailable}}
+	if p := o.Problem(nil, err); p.Status != 400 || p.Code != "invalid" {
+		t.Fatalf("custom precedence ignored: %+v", p)
+	}
+	// Defects win regardless of precedence.
+	if p := o.Problem(nil, xgxerror.Join(err, xgxerror.Defect(errors.New("bug")))); p.Code != "defect" {
+		t.Fatalf("defect must win: %+v", p)
+	}
+}
+
+func TestWriteError_DetailAndInstance(t *testing.T) {
+	t.Parallel()
+
+	_, body := serve(t, failing(xgxerror.NotFound("user", 1)), "/users/1")
+	if body["detail"] != "user not found" || body["instance"] != "/users/1" {
+		t.Fatalf("detail/instance: %v", body)
+	}
+
+	// Request fields attached to a foreign wrapper keep the real detail.
+	ctx := xgxerror.WithContextFields(context.Background(), "request_id", "r1")
+	rec, body := serve(t, failing(xgxerror.CtxFrom(ctx, fmt.Errorf("repo: %w", xgxerror.NotFound("user", 42)))), "/")
+	if rec.Code != 404 || body["detail"] != "user not found" {
+		t.Fatalf("CtxFrom on a foreign wrapper: %d %v", rec.Code, body["detail"])
+	}
+
+	// Defects never leak their cause text; the registry default is used.
+	_, body = serve(t, failing(xgxerror.Defect(errors.New("nil map write at 0xdeadbeef"))), "/")
+	if body["detail"] != "defect" {
+		t.Fatalf("defect detail should use registry default, got %v", body["detail"])
+	}
+	if body["title"] != "Internal Server Error" {
+		t.Fatalf("title: %v", body["title"])
+	}
+}
+
+func TestWriteError_ServerMessagesNotEchoed(t *testing.T) {
+	t.Parallel()
+
+	secret := "pq: password auth failed"
+	cases := []error{
+		xgxerror.Internal(errors.New(secret)),
+		xgxerror.Wrap(errors.New("dial"), secret),
+		xgxerror.Recode(nil, xgxerror.CodeUnavailable).MsgReplace(secret),
+		xgxerror.Join(xgxerror.Invalid("email", "format"), xgxerror.New(secret)),
+	}
+	for _, err := range cases {
+		rec, body := serve(t, failing(err), "/")
+		if strings.Contains(rec.Body.String(), "pq:") {
+			t.Fatalf("server message leaked for %v: %s", err, rec.Body.String())
+		}
+		if body["detail"] == "" || body["detail"] == nil {
+			t.Fatalf("detail should fall back to a generic message: %v", body)
+		}
+	}
+}
+
+func TestOptions_StatusOverridesAndTypeBase(t *testing.T) {
+	t.Parallel()
+
+	o := &Options{
+		Statuses: map[xgxerror.Code]int{xgxerror.CodeUnavailable: 502},
+		TypeBase: "https://errors.example.com/",
+	}
+	rec, body := serve(t, o.Handler(failing(xgxerror.Recode(nil, "unavailable.db.primary"))), "/")
+	if rec.Code != 502 {
+		t.Fatalf("override should apply to descendants: got %d", rec.Code)
+	}
+	if body["type"] != "https://errors.example.com/unavailable.db.primary" {
+		t.Fatalf("type: %v", body["type"])
+	}
+}
+
+func TestOptions_CustomRegistryStatus(t *testing.T) {
+	t.Parallel()
+
+	reg := xgxerror.NewRegistry()
+	reg.MustRegister(xgxerror.CodeInfo{Code: "payment_declined", HTTPStatus: 402, DefaultMessage: "payment declined"})
+	o := &Options{Registry: reg}
+
+	p := o.Problem(nil, xgxerror.Recode(nil, "payment_declined").MsgReplace(""))
+	if p.Status != 402 || p.Detail != "payment declined" || p.Title != "Payment Required" {
+		t.Fatalf("custom registry mapping: %+v", p)
+	}
+	if p.Instance != "" {
+		t.Fatalf("instance should be empty without a request")
+	}
+}
+
+func TestOptions_AllowListedFieldsOnly(t *testing.T) {
+	t.Parallel()
+
+	err := xgxerror.Wrap(
+		xgxerror.Invalid("email", "format").With("tenant", "inner"),
+		"", "tenant", "outer", "password", "hunter2", "internal_host", "db-7",
+	)
+	o := &Options{Fields: []string{"tenant", "field", "password"}}
+	_, body := serve(t, o.Handler(failing(err)), "/")
+
+	if body["tenant"] != "outer" {
+		// Wrap augments the native error in place, so the newest value wins.
+		t.Fatalf("tenant: %v", body["tenant"])
+	}
+	if body["field"] != "email" {
+		t.Fatalf("field: %v", body["field"])
+	}
+	if body["password"] != xgxerror.RedactedText {
+		t.Fatalf("sensitive allow-listed field must stay redacted: %v", body["password"])
+	}
+	if _, leaked := body["internal_host"]; leaked {
+		t.Fatalf("non-allow-listed field leaked: %v", body)
+	}
+}
+
+func TestOptions_OutermostFieldWinsAcrossNodes(t *testing.T) {
+	t.Parallel()
+
+	inner := xgxerror.NotFound("user", 1).With("tenant", "inner")
+	outer := xgxerror.Internal(inner).With("tenant", "outer")
+	p := (&Options{Fields: []string{"tenant", "entity"}}).Problem(nil, outer)
+	if p.Extensions["tenant"] != "outer" || p.Extensions["entity"] != "user" {
+		t.Fatalf("extensions: %v", p.Extensions)
+	}
+}
+
+func TestHandlerFunc_SuccessWritesNothingExtra(t *testing.T) {
+	t.Parallel()
+
+	h := HandlerFunc(func(w http.ResponseWriter, _ *http.Request) error {
+		_, _ = w.Write([]byte("ok"))
+		return nil
+	})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != 200 || rec.Body.String() != "ok" {
+		t.Fatalf("success path altered: %d %q", rec.Code, rec.Body.String())
+	}
+	if NewProblem(nil, nil) != nil {
+		t.Fatalf("NewProblem(nil) should be nil")
+	}
+}
+
+func TestProblem_JSONRoundTripKeepsExtensions(t *testing.T) {
+	t.Parallel()
+
+	p := &Problem{
+		Type: "urn:x", Status: 409, Detail: "dup", Code: "conflict",
+		Extensions: map[string]any{"tenant": "acme", "status": "ignored", "count": 3},
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if strings.Contains(string(data), "ignored") {
+		t.Fatalf("extension overrode a standard member: %s", data)
+	}
+	var back Problem
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if back.Status != 409 || back.Code != "conflict" || back.Extensions["tenant"] != "acme" {
+		t.Fatalf("round trip: %+v", back)
+	}
+	if n, ok := back.Extensions["count"].(json.Number); !ok || n.String() != "3" {
+		t.Fatalf("numbers should decode as json.Number: %#v", back.Extensions["count"])
+	}
+}
@@ -0,0 +1,209 @@
+// inspect.go — read-only node introspection and reconstruction for adapters.
+//
+// Purpose:
+//   - Give exporters (JSON, structured logs, transport adapters) an exported,
+//     lossless view of ONE error node without widening the Error interface.
+//   - Let decoders rebuild native nodes from that view, so classification
+//     (CodeOf/HasCode/IsDefect/IsInterrupt) and typed fields keep working
+//     after an error crosses a process boundary.
+//
+// Semantics:
+//   - Inspect describes a single node; callers recurse via Node.Cause and
+//     Node.Errors. It never traverses on its own.
+//   - Node.Fields is a defensive copy in insertion order (duplicates kept).
+//   - Restore never captures a stack; it reuses whatever Node.Stack holds.
+//   - Exporters render Node.Stack.Filtered() so the StackPolicy applies.
+//   - Return traces are process-local (raw PCs) and are not part of Node;
+//     read them with ReturnTrace before exporting if needed.
+//   - Foreign nodes restore to an opaque error that preserves the message and
+//     unwrap shape, not the original dynamic type.
+package xgxerror
+
+// Kind identifies the concrete category of a single error node.
+type Kind uint8
+
+const (
+	// KindForeign is any error not implemented by this package.
+	KindForeign Kind = iota
+	// KindFailure is an expected, recoverable failure (NotFound, Internal, ...).
+	KindFailure
+	// KindDefect is a programming defect (Defect).
+	KindDefect
+	// KindInterrupt is a cooperative cancellation (Interrupt, InterruptDeadline).
+	KindInterrupt
+	// KindJoin is the multi-error container returned by Join.
+	KindJoin
+)
+
+// String returns the lowercase name of the kind (e.g., "failure").
+func (k Kind) String() string {
+	switch k {
+	case KindFailure:
+		return "failure"
+	case KindDefect:
+		return "defect"
+	case KindInterrupt:
+		return "interrupt"
+	case KindJoin:
+		return "join"
+	default:
+		return "foreign"
+	}
+}
+
+// Node is an exported snapshot of a single error node.
+//
+// For foreign errors only Kind, Msg (the Error() string), Cause and Errors are
+// populated. For KindJoin only Kind and Errors are populated.
+type Node struct {
+	Kind    Kind
+	Code    Code    // classification; CodeDefect/CodeInterrupt for those kinds
+	Msg     string  // raw message WITHOUT the code prefix added by Error()
+	Fields  []Field // ordered context (copy); duplicates preserved
+	Pinned  []bool  // Pinned[i]: Fields[i] survives CtxBound (nil when none is pinned)
+	Stack   Stack   // captured stack, if any
+	Details []any   // typed detail payloads, oldest first (copy; see WithDetail)
+	Cause   error   // single-unwrap parent, if any
+	Errors  []error // multi-unwrap children, if any (copy)
+}
+
+// CauseStack returns the nearest stack in n's single-cause chain (empty if
+// none). Exporters elide n.Stack.CommonSuffix(n.CauseStack()) trailing
+// frames, mirroring "... N frames in common with cause" in %+v.
+func (n Node) CauseStack() Stack { return causeStack(n.Cause) }
+
+// Inspect returns a snapshot of err's outermost node.
+// Inspect(nil) returns the zero Node.
+func Inspect(err error) Node {
+	switch e := err.(type) {
+	case nil:
+		return Node{}
+	case *failureErr:
+		n := Node{Kind: KindFailure, Code: e.code, Msg: e.msg, Stack: e.stk, Details: copyDetails(e.details), Cause: e.cause}
+		n.Fields, n.Pinned = exportFields(e.ctx)
+		return n
+	case *defectErr:
+		n := Node{Kind: KindDefect, Code: CodeDefect, Msg: e.msg, Stack: e.stk, Details: copyDetails(e.details), Cause: e.cause}
+		n.Fields, n.Pinned = exportFields(e.ctx)
+		return n
+	case *interruptErr:
+		n := Node{Kind: KindInterrupt, Code: CodeInterrupt, Msg: e.msg, Details: copyDetails(e.details), Cause: e.cause}
+		n.Fields, n.Pinned = exportFields(e.ctx)
+		return n
+	case *multi:
+		return Node{Kind: KindJoin, Errors: copyErrors(e.errs)}
+	}
+	n := Node{Kind: KindForeign, Msg: err.Error()}
+	if m, ok := err.(multiUnwrapper); ok {
+		n.Errors = copyErrors(m.Unwrap())
+	} else if s, ok := err.(singleUnwrapper); ok {
+		n.Cause = s.Unwrap()
+	}
+	return n
+}
+
+// Restore builds an error from a Node, typically one produced by a decoder.
+//
+//   - KindFailure/KindDefect/KindInterrupt → native Error with the given
+//     message, fields, stack, details and cause (interrupts ignore Stack; a
+//     nil interrupt cause defaults to context.Canceled via Interrupt semantics).
+//     A failure with neither message nor code but a cause is restored as a
+//     tag (see Adopt).
+//   - KindJoin → Join(n.Errors...) (nil, identity or *multi).
+//   - KindForeign → opaque error whose Error() is n.Msg and whose Unwrap exposes
+//     n.Errors (if any) or n.Cause.
+func Restore(n Node) error {
+	switch n.Kind {
+	case KindFailure:
+		tag := n.Msg == "" && n.Code == "" && n.Cause != nil // only Adopt builds these
+		return &failureErr{msg: n.Msg, code: n.Code, ctx: importFields(n.Fields, n.Pinned), cause: n.Cause, stk: n.Stack, details: copyDetails(n.Details), tag: tag}
+	case KindDefect:
+		return &defectErr{msg: n.Msg, ctx: importFields(n.Fields, n.Pinned), cause: n.Cause, stk: n.Stack, details: copyDetails(n.Details)}
+	case KindInterrupt:
+		ie := Interrupt(n.Msg).(*interruptErr)
+		ie.ctx = importFields(n.Fields, n.Pinned)
+		ie.details = copyDetails(n.Details)
+		if n.Cause != nil {
+			ie.cause = n.Cause
+		}
+		return ie
+	case KindJoin:
+		return Join(n.Errors...)
+	}
+	if len(n.Errors) > 0 {
+		return &opaqueMulti{msg: n.Msg, errs: copyErrors(n.Errors)}
+	}
+	return &opaqueErr{msg: n.Msg, cause: n.Cause}
+}
+
+// opaqueErr stands in for a restored foreign error with at most one cause.
+type opaqueErr struct {
+	msg   string
+	cause error
+}
+
+func (e *opaqueErr) Error() string { return e.msg }
+func (e *opaqueErr) Unwrap() error { return e.cause }
+
+// opaqueMulti stands in for a restored foreign error with several children.
+type opaqueMulti struct {
+	msg  string
+	errs []error
+}
+
+func (e *opaqueMulti) Error() string   { return e.msg }
+func (e *opaqueMulti) Unwrap() []error { return e.errs }
+
+// exportFields returns isolated copies of fs's fields and, if any field is
+// pinned, their pin states (nil otherwise).
+func exportFields(fs fields) ([]Field, []bool) {
+	if len(fs) == 0 {
+		return nil, nil
+	}
+	out := make([]Field, len(fs))
+	var pinned []bool
+	for i, f := range fs {
+		out[i] = f.Field
+		if f.pinned {
+			if pinned == nil {
+				pinned = make([]bool, len(fs))
+			}
+			pinned[i] = true
+		}
+	}
+	return out, pinned
+}
+
+// importFields builds internal fields from fs and their pin states (missing
+// entries are unpinned), emptyFields when fs is empty.
+func importFields(fs []Field, pinned []bool) fields {
+	if len(fs) == 0 {
+		return emptyFields
+	}
+	out := make(fields, len(fs))
+	for i, f := range fs {
+		out[i] = ctxField{Field: f, pinned: i < len(pinned) && pinned[i]}
+	}
+	return out
+}
+
+// copyErrors returns an isolated copy of errs (nil when empty).
+func copyErrors(errs []error) []error {
+	if len(errs) == 0 {
+		return nil
+	}
+	out := make([]error, len(errs))
+	copy(out, errs)
+	return out
+}
+
+// copyDetails returns an isolated copy of ds (nil when empty). The values
+// themselves are shared, not deep-copied.
+func copyDetails(ds []any) []any {
+	if len(ds) == 0 {
+		return nil
+	}
+	out := make([]any, len(ds))
+	copy(out, ds)
+	return out
+}
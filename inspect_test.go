@@ -0,0 +1,123 @@
+// inspect_test.go — verification of node introspection and reconstruction.
+package xgxerror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestInspect_NativeKinds(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		err  error
+		kind Kind
+		code Code
+		msg  string
+	}{
+		{"failure", NotFound("user", 1), KindFailure, CodeNotFound, "user not found"},
+		{"defect", Defect(errors.New("x")).MsgReplace("bug"), KindDefect, CodeDefect, "bug"},
+		{"interrupt", Interrupt("stop"), KindInterrupt, CodeInterrupt, "stop"},
+		{"join", Join(BadRequest("a"), BadRequest("b")), KindJoin, "", ""},
+		{"foreign", errors.New("plain"), KindForeign, "", "plain"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := Inspect(tc.err)
+			if n.Kind != tc.kind || n.Code != tc.code || n.Msg != tc.msg {
+				t.Fatalf("Inspect = {%v %q %q}, want {%v %q %q}", n.Kind, n.Code, n.Msg, tc.kind, tc.code, tc.msg)
+			}
+			if n.Kind.String() != tc.name {
+				t.Fatalf("Kind.String() = %q, want %q", n.Kind.String(), tc.name)
+			}
+		})
+	}
+
+	if n := Inspect(nil); n.Kind != KindForeign || n.Msg != "" || n.Cause != nil {
+		t.Fatalf("Inspect(nil) should be the zero Node, got %+v", n)
+	}
+}
+
+func TestInspect_FieldsAreCopiesInOrder(t *testing.T) {
+	t.Parallel()
+
+	e := BadRequest("x").Ctx("", "a", 1, "b", 2, "a", 3)
+	n := Inspect(e)
+	if len(n.Fields) != 3 || n.Fields[0].Key != "a" || n.Fields[2].Val != 3 {
+		t.Fatalf("unexpected fields: %#v", n.Fields)
+	}
+	n.Fields[0].Val = "mutated"
+	if v := e.Context()["b"]; v != 2 {
+		t.Fatalf("unexpected context: %v", v)
+	}
+	if Inspect(e).Fields[0].Val != 1 {
+		t.Fatalf("Inspect leaked internal slice; mutation visible")
+	}
+}
+
+func TestInspect_ForeignUnwrapShape(t *testing.T) {
+	t.Parallel()
+
+	inner := errors.New("inner")
+	if n := Inspect(fmt.Errorf("outer: %w", inner)); n.Cause != inner || n.Errors != nil {
+		t.Fatalf("single unwrap not reported: %+v", n)
+	}
+	if n := Inspect(errors.Join(inner, inner)); len(n.Errors) != 2 || n.Cause != nil {
+		t.Fatalf("multi unwrap not reported: %+v", n)
+	}
+}
+
+func TestRestore_RoundTripsNativeNodes(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("db down")
+	for _, src := range []error{
+		Internal(cause).Ctx("", "table", "users"),
+		Defect(cause).With("k", "v"),
+		InterruptDeadline("late").With("attempt", 2),
+	} {
+		got := Restore(Inspect(src))
+		if got.Error() != src.Error() {
+			t.Fatalf("Error(): want %q got %q", src.Error(), got.Error())
+		}
+		if CodeOf(got) != CodeOf(src) {
+			t.Fatalf("CodeOf: want %q got %q", CodeOf(src), CodeOf(got))
+		}
+		if fmt.Sprintf("%+v", got) != fmt.Sprintf("%+v", src) {
+			t.Fatalf("%%+v differs:\nwant:\n%+v\ngot:\n%+v", src, got)
+		}
+	}
+}
+
+func TestRestore_InterruptDefaultsToCanceled(t *testing.T) {
+	t.Parallel()
+
+	got := Restore(Node{Kind: KindInterrupt, Msg: "stop"})
+	if !errors.Is(got, context.Canceled) || !IsInterrupt(got) {
+		t.Fatalf("restored interrupt should unwrap to context.Canceled")
+	}
+}
+
+func TestRestore_JoinAndForeign(t *testing.T) {
+	t.Parallel()
+
+	a, b := BadRequest("a"), Conflict("b")
+	if got := Restore(Node{Kind: KindJoin, Errors: []error{a, nil, b}}); got.Error() != "bad_request: a\nconflict: b" {
+		t.Fatalf("join restore: %q", got.Error())
+	}
+	if got := Restore(Node{Kind: KindJoin}); got != nil {
+		t.Fatalf("empty join should restore to nil, got %v", got)
+	}
+
+	f := Restore(Node{Kind: KindForeign, Msg: "wrapped", Cause: a})
+	if f.Error() != "wrapped" || !errors.Is(f, a) {
+		t.Fatalf("foreign single restore: %v", f)
+	}
+	m := Restore(Node{Kind: KindForeign, Msg: "many", Errors: []error{a, b}})
+	if m.Error() != "many" || !errors.Is(m, b) || !HasCode(m, CodeConflict) {
+		t.Fatalf("foreign multi restore: %v", m)
+	}
+}
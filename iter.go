@@ -0,0 +1,121 @@
+// iter.go — range-over-func iterators for error graphs and context fields.
+//
+// Walk forces callback style and Flatten allocates the whole leaf slice; the
+// iterators here expose the same traversals lazily, so callers can range over
+// them and break early:
+//
+//	for e := range xgxerror.All(err) {
+//	    if xgxerror.IsRetryable(e) { ... break }
+//	}
+//
+// Semantics:
+//   - All:         same nodes and order as Walk (pre-order, distinct, cycle-safe).
+//   - Leaves:      same nodes and order as Flatten, produced on demand.
+//   - AllWithPath: All plus each node's Path (ancestors and branch indices).
+//   - Fields:      a node's own context in insertion order, duplicates
+//     included (Context() collapses both into a map), redacted like Context().
+//
+// Allocation: a node without children is yielded without any bookkeeping, and
+// Fields never allocates for native errors. A Path is reused between
+// iterations; Clone it to keep it past the loop body.
+package xgxerror
+
+import (
+	"iter"
+	"sort"
+)
+
+// All returns an iterator over every distinct node of err's graph in Walk
+// order. A nil err yields nothing.
+func All(err error) iter.Seq[error] {
+	return func(yield func(error) bool) {
+		if err == nil {
+			return
+		}
+		if !hasChildren(err) {
+			yield(err)
+			return
+		}
+		Walk(err, yield)
+	}
+}
+
+// Leaves returns an iterator over err's leaf errors in Flatten order, without
+// collecting them first.
+func Leaves(err error) iter.Seq[error] {
+	return func(yield func(error) bool) {
+		if err == nil {
+			return
+		}
+		if !hasChildren(err) {
+			yield(err)
+			return
+		}
+		walkLeaves(err, yield)
+	}
+}
+
+// AllWithPath is All with each node's Path. The yielded Path is reused by the
+// iterator; Clone it to retain it.
+func AllWithPath(err error) iter.Seq2[Path, error] {
+	return func(yield func(Path, error) bool) {
+		if err == nil {
+			return
+		}
+		if !hasChildren(err) {
+			yield(nil, err)
+			return
+		}
+		WalkPath(err, yield)
+	}
+}
+
+// Fields returns an iterator over e's OWN context fields (not its causes') in
+// insertion order, keeping duplicate keys. Values are redacted exactly as in
+// Context() (sensitive keys and Secret values yield RedactedText; use Reveal
+// for raw values). Errors implemented outside this package yield their
+// Context() map in key order. A nil e yields nothing.
+func Fields(e Error) iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		var fs fields
+		switch x := e.(type) {
+		case nil:
+			return
+		case *failureErr:
+			fs = x.ctx
+		case *defectErr:
+			fs = x.ctx
+		case *interruptErr:
+			fs = x.ctx
+		default:
+			m := x.Context()
+			keys := make([]string, 0, len(m))
+			for k := range m {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				if !yield(k, m[k]) {
+					return
+				}
+			}
+			return
+		}
+		for _, f := range fs {
+			if r := f.Redacted(); !yield(r.Key, r.Val) {
+				return
+			}
+		}
+	}
+}
+
+// hasChildren reports whether err currently unwraps to at least one error.
+func hasChildren(err error) bool {
+	switch x := err.(type) {
+	case multiUnwrapper:
+		return len(x.Unwrap()) > 0
+	case singleUnwrapper:
+		return x.Unwrap() != nil
+	}
+	return false
+}
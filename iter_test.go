@@ -0,0 +1,152 @@
+// iter_test.go — verification of range-over-func graph and field iterators.
+package xgxerror
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestAll_MatchesWalkAndBreaks(t *testing.T) {
+	t.Parallel()
+
+	shared := errors.New("shared")
+	err := Join(Wrap(shared, "a"), fmt.Errorf("b: %w", shared), NotFound("user", 1))
+
+	var walked []error
+	Walk(err, func(e error) bool { walked = append(walked, e); return true })
+	got := slices.Collect(All(err))
+	if len(got) != len(walked) {
+		t.Fatalf("All yielded %d nodes, Walk %d", len(got), len(walked))
+	}
+	for i := range got {
+		if got[i] != walked[i] {
+			t.Fatalf("node %d: All %v, Walk %v", i, got[i], walked[i])
+		}
+	}
+
+	n := 0
+	for range All(err) {
+		n++
+		if n == 2 {
+			break
+		}
+	}
+	if n != 2 {
+		t.Fatalf("early break: %d", n)
+	}
+	if len(slices.Collect(All(nil))) != 0 {
+		t.Fatalf("nil should yield nothing")
+	}
+}
+
+func TestLeaves_MatchesFlatten(t *testing.T) {
+	t.Parallel()
+
+	err := Join(Wrap(errors.New("x"), "a"), Join(errors.New("y"), Timeout(0)), errors.New("z"))
+	want := Flatten(err)
+	got := slices.Collect(Leaves(err))
+	if len(got) != len(want) || len(got) != 4 {
+		t.Fatalf("Leaves = %v, Flatten = %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("leaf %d: %v vs %v", i, got[i], want[i])
+		}
+	}
+	for e := range Leaves(err) {
+		if e != want[0] {
+			t.Fatalf("first leaf %v", e)
+		}
+		break
+	}
+}
+
+func TestAllWithPath_AncestorsAndBranches(t *testing.T) {
+	t.Parallel()
+
+	leaf := errors.New("disk full")
+	task := Wrap(leaf, "task 2")
+	batch := Join(NotFound("user", 1), Join(Timeout(0), task))
+
+	var paths []string
+	var found Path
+	for p, e := range AllWithPath(batch) {
+		paths = append(paths, p.String())
+		if e == leaf {
+			found = p.Clone()
+		}
+	}
+	want := []string{"$", "$[0]", "$[1]", "$[1][0]", "$[1][1]", "$[1][1].cause"}
+	if !slices.Equal(paths, want) {
+		t.Fatalf("paths:\n got %v\nwant %v", paths, want)
+	}
+	if found.Depth() != 3 || found.Parent() != task || found[0].Err != batch ||
+		!slices.Equal(found.Branches(), []int{1, 1}) {
+		t.Fatalf("leaf path: %v depth=%d branches=%v", found, found.Depth(), found.Branches())
+	}
+	for p, e := range AllWithPath(leaf) {
+		if p.Depth() != 0 || p.Parent() != nil || e != leaf || p.String() != "$" {
+			t.Fatalf("root path: %v", p)
+		}
+	}
+}
+
+func TestFields_OrderedWithDuplicates(t *testing.T) {
+	t.Parallel()
+
+	e := New("x", "a", 1, "b", 2).With("a", 3)
+	var got []string
+	for k, v := range Fields(e) {
+		got = append(got, fmt.Sprintf("%s=%v", k, v))
+	}
+	if !slices.Equal(got, []string{"a=1", "b=2", "a=3"}) {
+		t.Fatalf("Fields = %v", got)
+	}
+	for k := range Fields(e) {
+		if k != "a" {
+			t.Fatalf("first key %q", k)
+		}
+		break
+	}
+	for range Fields(nil) {
+		t.Fatalf("nil should yield nothing")
+	}
+}
+
+func TestFields_Redacts(t *testing.T) {
+	t.Parallel()
+
+	e := New("x").With("password", "hunter2").With("api", Secret("k")).With("user", 7)
+	var got []string
+	for k, v := range Fields(e) {
+		got = append(got, fmt.Sprintf("%s=%v", k, v))
+	}
+	want := []string{"password=" + RedactedText, "api=" + RedactedText, "user=7"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Fields = %v, want %v", got, want)
+	}
+}
+
+func TestIterators_LeafDoesNotAllocate(t *testing.T) {
+	// No t.Parallel — allocation tests must run serially.
+	e := NotFound("user", 1).With("tenant", "acme")
+	allocs := testing.AllocsPerRun(100, func() {
+		for x := range All(e) {
+			_ = x
+		}
+		for x := range Leaves(e) {
+			_ = x
+		}
+		for p, x := range AllWithPath(e) {
+			_, _ = p, x
+		}
+		for k, v := range Fields(e) {
+			_, _ = k, v
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("allocs = %v, want 0", allocs)
+	}
+}
@@ -46,14 +46,25 @@ func (m *multi) Error() string {
 // Unwrap exposes the children to stdlib traversal (errors.Is/As will walk pre-order).
 func (m *multi) Unwrap() []error { return m.errs }
 
+// CodeVal reports the aggregate code of the children: DominantCode under
+// DefaultPrecedence ("" if no child carries a code). CodeOf is unaffected;
+// it still returns the first child code in DFS order. The code predicates
+// (IsDefect, IsInterrupt) skip this aggregate and inspect the branches.
+func (m *multi) CodeVal() Code { return DominantCode(m, nil) }
+
 // Format implements fmt.Formatter.
 //
 //	%v, %s       → render like Error() (concise, stdlib-compatible).
 //	%q           → quoted Error() (concise, stdlib-compatible).
 //	%+v          → recurse into children and render each with %+v, newline-separated.
+//	%#v          → unwrap graph as an indented tree (see Tree).
 func (m *multi) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
+		if s.Flag('#') {
+			fmt.Fprint(s, Tree(m))
+			return
+		}
 		if s.Flag('+') {
 			for i, e := range m.errs {
 				if i > 0 {
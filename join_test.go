@@ -164,3 +164,19 @@ func TestJoinedErrors_WorkWithWalk(t *testing.T) {
 		t.Fatalf("Walk did not reach both leaves: leaf1=%v leaf2=%v", sawLeaf1, sawLeaf2)
 	}
 }
+
+func TestJoin_CodeValReportsDominantCode(t *testing.T) {
+	t.Parallel()
+
+	j := Join(Invalid("email", "format"), Internal(errors.New("db")))
+	var c interface{ CodeVal() Code }
+	if !errors.As(j, &c) || c.CodeVal() != CodeInternal {
+		t.Fatalf("aggregate code should be internal, got %v", c)
+	}
+	if CodeOf(j) != CodeInvalid {
+		t.Fatalf("CodeOf keeps first-in-DFS semantics, got %q", CodeOf(j))
+	}
+	if got := Join(errors.New("a"), errors.New("b")).(interface{ CodeVal() Code }).CodeVal(); got != "" {
+		t.Fatalf("codeless children aggregate to empty code, got %q", got)
+	}
+}
@@ -0,0 +1,358 @@
+// jsonx.go — lossless JSON encoding of xgx error graphs.
+//
+// Package jsonx marshals any error graph (failures, defects, interrupts,
+// xgxerror.Join containers and foreign errors) into a stable JSON document and
+// decodes it back into native xgx errors.
+//
+// Document shape (one object per node, recursive):
+//
+//	{
+//	  "kind":   "failure" | "defect" | "interrupt" | "join" | "foreign",
+//	  "code":   "not_found",                      // omitted when empty
+//	  "msg":    "user not found",                 // raw message (no code prefix)
+//	  "ctx":    [{"key":"id","type":"int","value":42}, ...],
+//	  "details": [{"type":"quota","value":{...}}], // typed payloads, oldest first
+//	  "stack":  [{"function":"pkg.F","file":"/src/f.go","line":12}, ...],
+//	  "cause":  { ...node... },                    // single-unwrap parent
+//	  "errors": [ { ...node... }, ... ],           // multi-unwrap children
+//	  "sentinel": "context.Canceled"               // well-known foreign leaf
+//	}
+//
+// Round-trip guarantees:
+//   - Kinds, codes, messages and field order survive, so CodeOf, HasCode,
+//     IsDefect, IsInterrupt and FieldOf[T].Get keep working after decoding.
+//   - Field values keep their Go type for strings, bools, all sized ints/uints
+//     and floats (NaN and ±Inf included), time.Duration and time.Time. Other types decode to their
+//     registered type (see RegisterType) or to generic JSON values.
+//   - Registered sentinels (context.Canceled and context.DeadlineExceeded by
+//     default) decode to the identical value, so errors.Is keeps working.
+//   - Sensitive fields (see xgxerror.Secret and SetRedactedKeys) are exported
+//     as "[REDACTED]" with type "redacted" and decode to a redacted
+//     xgxerror.SecretValue; their raw value never leaves the process.
+//   - Details (see xgxerror.WithDetail) use the same type tags as field
+//     values, so registered types decode back to T and DetailOf[T] keeps
+//     working; Secret details export as "redacted".
+//   - Foreign errors decode to opaque errors preserving message and unwrap shape.
+//   - Stack frames keep function/file/line as rendered by the stack's
+//     StackPolicy; program counters are not exported. Frames shared with the
+//     cause's stack are elided and counted in stack_common, then restored
+//     on decode.
+package jsonx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	xgxerror "github.com/tuliorib/xgx-error"
+)
+
+// maxDepth bounds recursion against pathological (cyclic) unwrap graphs.
+const maxDepth = 1 << 10
+
+// Node is the JSON representation of a single error node.
+type Node struct {
+	Kind    string   `json:"kind"`
+	Code    string   `json:"code,omitempty"`
+	Msg     string   `json:"msg"`
+	Ctx     []Field  `json:"ctx,omitempty"`
+	Details []Detail `json:"details,omitempty"`
+	Stack   []Frame  `json:"stack,omitempty"`
+	// StackCommon counts trailing frames elided from Stack because they
+	// equal the tail of the nearest stack in the cause chain.
+	StackCommon int     `json:"stack_common,omitempty"`
+	Cause       *Node   `json:"cause,omitempty"`
+	Errors      []*Node `json:"errors,omitempty"`
+	Sentinel    string  `json:"sentinel,omitempty"`
+}
+
+// Field is one ordered context field with an explicit type tag.
+type Field struct {
+	Key    string          `json:"key"`
+	Type   string          `json:"type"`
+	Value  json.RawMessage `json:"value"`
+	Pinned bool            `json:"pinned,omitempty"` // exempt from CtxBound eviction
+}
+
+// Detail is one typed detail payload (see xgxerror.WithDetail); Type uses the
+// same tags as Field.Type.
+type Detail struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Frame is one stack frame (most recent call first).
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// Marshal encodes err as a JSON document. Marshal(nil) yields "null".
+func Marshal(err error) ([]byte, error) {
+	return json.Marshal(Encode(err))
+}
+
+// Unmarshal decodes a document produced by Marshal and stores the rebuilt
+// error in *dst. A "null" document stores nil.
+func Unmarshal(data []byte, dst *error) error {
+	if dst == nil {
+		return fmt.Errorf("jsonx: Unmarshal(nil destination)")
+	}
+	var n *Node
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	out, err := n.Decode()
+	if err != nil {
+		return err
+	}
+	*dst = out
+	return nil
+}
+
+// Encode converts err into its document form. Encode(nil) returns nil.
+func Encode(err error) *Node {
+	return encode(err, 0)
+}
+
+func encode(err error, depth int) *Node {
+	if err == nil {
+		return nil
+	}
+	if name, ok := sentinelName(err); ok {
+		return &Node{Kind: xgxerror.KindForeign.String(), Msg: err.Error(), Sentinel: name}
+	}
+
+	in := xgxerror.Inspect(err)
+	out := &Node{
+		Kind: in.Kind.String(),
+		Code: string(in.Code),
+		Msg:  in.Msg,
+	}
+	for i, f := range in.Fields {
+		out.Ctx = append(out.Ctx, encodeField(f, i < len(in.Pinned) && in.Pinned[i]))
+	}
+	for _, d := range in.Details {
+		out.Details = append(out.Details, encodeDetail(d))
+	}
+	frames := in.Stack.Filtered()
+	if depth < maxDepth {
+		// Only elide when the cause (and its stack) is encoded too.
+		out.StackCommon = in.Stack.CommonSuffix(in.CauseStack())
+	}
+	for _, fr := range frames[:len(frames)-out.StackCommon] {
+		out.Stack = append(out.Stack, Frame{Function: fr.Function, File: fr.File, Line: fr.Line})
+	}
+
+	if depth >= maxDepth {
+		return out // truncate children; the message still carries the text
+	}
+	if in.Cause != nil {
+		out.Cause = encode(in.Cause, depth+1)
+	}
+	for _, c := range in.Errors {
+		if c != nil {
+			out.Errors = append(out.Errors, encode(c, depth+1))
+		}
+	}
+	return out
+}
+
+// Decode rebuilds the error described by n. A nil Node decodes to nil.
+func (n *Node) Decode() (error, error) {
+	return n.decode(0)
+}
+
+func (n *Node) decode(depth int) (error, error) {
+	if n == nil {
+		return nil, nil
+	}
+	if depth > maxDepth {
+		return nil, fmt.Errorf("jsonx: document exceeds max depth %d", maxDepth)
+	}
+	if n.Sentinel != "" {
+		if s, ok := sentinelByName(n.Sentinel); ok {
+			return s, nil
+		}
+		// Unknown sentinel: fall through to an opaque foreign node.
+	}
+
+	kind, ok := parseKind(n.Kind)
+	if !ok {
+		return nil, fmt.Errorf("jsonx: unknown node kind %q", n.Kind)
+	}
+
+	in := xgxerror.Node{
+		Kind: kind,
+		Code: xgxerror.Code(n.Code),
+		Msg:  n.Msg,
+	}
+	for i, f := range n.Ctx {
+		df, err := decodeField(f)
+		if err != nil {
+			return nil, err
+		}
+		in.Fields = append(in.Fields, df)
+		if f.Pinned {
+			if in.Pinned == nil {
+				in.Pinned = make([]bool, len(n.Ctx))
+			}
+			in.Pinned[i] = true
+		}
+	}
+	for i, d := range n.Details {
+		v, err := decodeValue(d.Type, d.Value)
+		if err != nil {
+			return nil, fmt.Errorf("jsonx: detail %d: %w", i, err)
+		}
+		if v != nil {
+			in.Details = append(in.Details, v)
+		}
+	}
+	if n.Cause != nil {
+		c, err := n.Cause.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		in.Cause = c
+	}
+	if len(n.Stack) > 0 || n.StackCommon != 0 {
+		// Validate before sizing anything from the untrusted count.
+		var shared []xgxerror.Frame
+		if n.StackCommon != 0 {
+			shared = in.CauseStack().Frames()
+			if n.StackCommon < 0 || n.StackCommon > len(shared) {
+				return nil, fmt.Errorf("jsonx: stack_common %d out of range for cause stack of %d frames", n.StackCommon, len(shared))
+			}
+		}
+		frames := make([]xgxerror.Frame, len(n.Stack), len(n.Stack)+n.StackCommon)
+		for i, fr := range n.Stack {
+			frames[i] = xgxerror.Frame{Function: fr.Function, File: fr.File, Line: fr.Line}
+		}
+		// Restore elided frames from the (already decoded) cause's stack.
+		frames = append(frames, shared[len(shared)-n.StackCommon:]...)
+		in.Stack = xgxerror.StackFromFrames(frames)
+	}
+	for _, child := range n.Errors {
+		c, err := child.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			in.Errors = append(in.Errors, c)
+		}
+	}
+	return xgxerror.Restore(in), nil
+}
+
+func parseKind(s string) (xgxerror.Kind, bool) {
+	for _, k := range []xgxerror.Kind{
+		xgxerror.KindFailure,
+		xgxerror.KindDefect,
+		xgxerror.KindInterrupt,
+		xgxerror.KindJoin,
+		xgxerror.KindForeign,
+	} {
+		if k.String() == s {
+			return k, true
+		}
+	}
+	return 0, false
+}
+
+// -----------------------------------------------------------------------------
+// Sentinels
+// -----------------------------------------------------------------------------
+
+var sentinels = struct {
+	sync.RWMutex
+	byName map[string]error
+}{
+	byName: map[string]error{
+		"context.Canceled":         context.Canceled,
+		"context.DeadlineExceeded": context.DeadlineExceeded,
+	},
+}
+
+// RegisterSentinel makes a foreign sentinel error survive a round trip by
+// identity, so errors.Is(decoded, sentinel) keeps working. Names must be
+// unique; RegisterSentinel panics on a duplicate name (like gob.Register).
+func RegisterSentinel(name string, err error) {
+	if name == "" || err == nil {
+		panic("jsonx: RegisterSentinel requires a name and a non-nil error")
+	}
+	sentinels.Lock()
+	defer sentinels.Unlock()
+	if _, dup := sentinels.byName[name]; dup {
+		panic(fmt.Sprintf("jsonx: sentinel %q already registered", name))
+	}
+	sentinels.byName[name] = err
+}
+
+func sentinelName(err error) (string, bool) {
+	if !reflect.TypeOf(err).Comparable() {
+		return "", false
+	}
+	sentinels.RLock()
+	defer sentinels.RUnlock()
+	for name, s := range sentinels.byName {
+		if s == err {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func sentinelByName(name string) (error, bool) {
+	sentinels.RLock()
+	defer sentinels.RUnlock()
+	s, ok := sentinels.byName[name]
+	return s, ok
+}
+
+// -----------------------------------------------------------------------------
+// Fields
+// -----------------------------------------------------------------------------
+
+func encodeField(f xgxerror.Field, pinned bool) Field {
+	if f.Sensitive() {
+		// Never export the raw value; keep the marking for downstream renderers.
+		return Field{Key: f.Key, Type: "redacted", Value: mustJSON(xgxerror.RedactedText), Pinned: pinned}
+	}
+	typ, raw := encodeValue(f.Val)
+	return Field{Key: f.Key, Type: typ, Value: raw, Pinned: pinned}
+}
+
+func decodeField(f Field) (xgxerror.Field, error) {
+	v, err := decodeValue(f.Type, f.Value)
+	if err != nil {
+		return xgxerror.Field{}, fmt.Errorf("jsonx: field %q: %w", f.Key, err)
+	}
+	return xgxerror.Field{Key: f.Key, Val: v}, nil
+}
+
+func encodeDetail(v any) Detail {
+	if (xgxerror.Field{Val: v}).Sensitive() {
+		return Detail{Type: "redacted", Value: mustJSON(xgxerror.RedactedText)}
+	}
+	typ, raw := encodeValue(v)
+	return Detail{Type: typ, Value: raw}
+}
+
+// mustJSON marshals v, falling back to a JSON string of its %v form.
+func mustJSON(v any) json.RawMessage {
+	b, err := json.Marshal(v)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprintf("%v", v))
+	}
+	return b
+}
+
+// isNull reports whether raw is absent or the JSON literal null.
+func isNull(raw json.RawMessage) bool {
+	return len(bytes.TrimSpace(raw)) == 0 || bytes.Equal(bytes.TrimSpace(raw), []byte("null"))
+}
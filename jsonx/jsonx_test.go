@@ -0,0 +1,452 @@
+// jsonx_test.go — round-trip verification of JSON encoding for error graphs.
+package jsonx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	xgxerror "github.com/tuliorib/xgx-error"
+)
+
+func roundTrip(t *testing.T, err error) error {
+	t.Helper()
+	data, mErr := Marshal(err)
+	if mErr != nil {
+		t.Fatalf("Marshal: %v", mErr)
+	}
+	var out error
+	if uErr := Unmarshal(data, &out); uErr != nil {
+		t.Fatalf("Unmarshal: %v\n--- doc ---\n%s", uErr, data)
+	}
+	return out
+}
+
+func TestRoundTrip_Nil(t *testing.T) {
+	t.Parallel()
+
+	data, err := Marshal(nil)
+	if err != nil || string(data) != "null" {
+		t.Fatalf("Marshal(nil) = %q, %v; want null", data, err)
+	}
+	out := errors.New("sentinel")
+	if err := Unmarshal(data, &out); err != nil || out != nil {
+		t.Fatalf("Unmarshal(null) = %v, %v; want nil, nil", out, err)
+	}
+}
+
+func TestRoundTrip_FailurePreservesCodeMsgAndTypedFields(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2025, 3, 4, 5, 6, 7, 8, time.UTC)
+	src := xgxerror.NotFound("user", 42).
+		Ctx("lookup failed", "tenant", "acme", "elapsed", 1500*time.Millisecond).
+		With("at", at).
+		With("ratio", float32(0.5)).
+		With("big", uint64(1<<63)).
+		With("none", nil)
+
+	got := roundTrip(t, src)
+
+	if got.Error() != src.Error() {
+		t.Fatalf("Error(): want %q got %q", src.Error(), got.Error())
+	}
+	if xgxerror.CodeOf(got) != xgxerror.CodeNotFound || !xgxerror.HasCode(got, xgxerror.CodeNotFound) {
+		t.Fatalf("code lost: CodeOf=%q", xgxerror.CodeOf(got))
+	}
+	xe, ok := got.(xgxerror.Error)
+	if !ok {
+		t.Fatalf("decoded %T does not implement Error", got)
+	}
+	if id, ok := xgxerror.FieldOf[int]("id").Get(xe); !ok || id != 42 {
+		t.Fatalf("FieldOf[int](id) = %v, %v", id, ok)
+	}
+	if d, ok := xgxerror.FieldOf[time.Duration]("elapsed").Get(xe); !ok || d != 1500*time.Millisecond {
+		t.Fatalf("FieldOf[Duration](elapsed) = %v, %v", d, ok)
+	}
+	if ts, ok := xgxerror.FieldOf[time.Time]("at").Get(xe); !ok || !ts.Equal(at) {
+		t.Fatalf("FieldOf[time.Time](at) = %v, %v", ts, ok)
+	}
+	if r, ok := xgxerror.FieldOf[float32]("ratio").Get(xe); !ok || r != 0.5 {
+		t.Fatalf("FieldOf[float32](ratio) = %v, %v", r, ok)
+	}
+	if b, ok := xgxerror.FieldOf[uint64]("big").Get(xe); !ok || b != 1<<63 {
+		t.Fatalf("FieldOf[uint64](big) = %v, %v", b, ok)
+	}
+
+	// Field order is preserved exactly.
+	want := xgxerror.Inspect(src).Fields
+	have := xgxerror.Inspect(got).Fields
+	if len(want) != len(have) {
+		t.Fatalf("field count: want %d got %d", len(want), len(have))
+	}
+	for i := range want {
+		if want[i].Key != have[i].Key {
+			t.Fatalf("field[%d] key: want %q got %q", i, want[i].Key, have[i].Key)
+		}
+	}
+}
+
+func TestRoundTrip_DefectKeepsStackAndCause(t *testing.T) {
+	t.Parallel()
+
+	src := xgxerror.Defect(errors.New("nil map")).Ctx("", "k", "v")
+	got := roundTrip(t, src)
+
+	if !xgxerror.IsDefect(got) {
+		t.Fatalf("IsDefect(decoded) = false")
+	}
+	if got.Error() != src.Error() {
+		t.Fatalf("Error(): want %q got %q", src.Error(), got.Error())
+	}
+	srcStk, gotStk := xgxerror.Inspect(src).Stack.Frames(), xgxerror.Inspect(got).Stack.Frames()
+	if len(gotStk) == 0 || len(gotStk) != len(srcStk) {
+		t.Fatalf("stack frames: want %d got %d", len(srcStk), len(gotStk))
+	}
+	if gotStk[0].Function != srcStk[0].Function || gotStk[0].Line != srcStk[0].Line {
+		t.Fatalf("top frame mismatch: want %+v got %+v", srcStk[0], gotStk[0])
+	}
+	if out := fmt.Sprintf("%+v", got); !strings.Contains(out, "stack:") {
+		t.Fatalf("%%+v on decoded defect lacks stack:\n%s", out)
+	}
+}
+
+func TestRoundTrip_ElidesAndRestoresCommonFrames(t *testing.T) {
+	t.Parallel()
+
+	src := xgxerror.Internal(xgxerror.Defect(errors.New("nil map")))
+	doc := Encode(src)
+	if doc.StackCommon == 0 {
+		t.Fatalf("nested stacks should share frames: %+v", doc)
+	}
+	got := roundTrip(t, src)
+	want, have := xgxerror.Inspect(src).Stack.Frames(), xgxerror.Inspect(got).Stack.Frames()
+	if len(want) != len(have) {
+		t.Fatalf("restored frames: want %d got %d", len(want), len(have))
+	}
+	for i := range want {
+		if want[i].Function != have[i].Function || want[i].Line != have[i].Line {
+			t.Fatalf("frame %d: want %+v got %+v", i, want[i], have[i])
+		}
+	}
+
+	for _, n := range []int{-1, math.MinInt, 1 << 10, math.MaxInt} {
+		doc.StackCommon = n
+		if _, err := doc.Decode(); err == nil || !strings.HasPrefix(err.Error(), "jsonx:") {
+			t.Fatalf("stack_common %d outside the cause stack must fail cleanly: %v", n, err)
+		}
+	}
+	leaf := &Node{Kind: "failure", Code: "internal", Msg: "x", StackCommon: -5}
+	if _, err := leaf.Decode(); err == nil {
+		t.Fatalf("stack_common without a cause stack must fail")
+	}
+}
+
+func TestRoundTrip_InterruptKeepsContextSentinel(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name string
+		err  xgxerror.Error
+		want error
+	}{
+		{"canceled", xgxerror.Interrupt("stop"), context.Canceled},
+		{"deadline", xgxerror.InterruptDeadline("late"), context.DeadlineExceeded},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := roundTrip(t, tc.err.With("attempt", 3))
+			if !xgxerror.IsInterrupt(got) {
+				t.Fatalf("IsInterrupt(decoded) = false")
+			}
+			if !errors.Is(got, tc.want) {
+				t.Fatalf("errors.Is(decoded, %v) = false", tc.want)
+			}
+			if got.Error() != tc.err.Error() {
+				t.Fatalf("Error(): want %q got %q", tc.err.Error(), got.Error())
+			}
+		})
+	}
+}
+
+func TestRoundTrip_JoinAndForeignErrors(t *testing.T) {
+	t.Parallel()
+
+	foreign := fmt.Errorf("dial: %w", errors.New("connection refused"))
+	src := xgxerror.Join(
+		xgxerror.Invalid("email", "format"),
+		xgxerror.Internal(foreign),
+		errors.Join(xgxerror.Unavailable("db"), io.EOF),
+	)
+
+	got := roundTrip(t, src)
+
+	if got.Error() != src.Error() {
+		t.Fatalf("Error(): want\n%s\ngot\n%s", src.Error(), got.Error())
+	}
+	for _, c := range []xgxerror.Code{xgxerror.CodeInvalid, xgxerror.CodeInternal, xgxerror.CodeUnavailable} {
+		if !xgxerror.HasCode(got, c) {
+			t.Fatalf("HasCode(decoded, %q) = false", c)
+		}
+	}
+	if !xgxerror.IsRetryable(got) {
+		t.Fatalf("IsRetryable(decoded) = false; unavailable branch lost")
+	}
+	if n := len(xgxerror.Flatten(got)); n != len(xgxerror.Flatten(src)) {
+		t.Fatalf("leaf count: want %d got %d", len(xgxerror.Flatten(src)), n)
+	}
+	if xgxerror.Inspect(got).Kind != xgxerror.KindJoin {
+		t.Fatalf("top-level kind: want join got %v", xgxerror.Inspect(got).Kind)
+	}
+}
+
+func TestRoundTrip_AdoptedTagsStayTransparent(t *testing.T) {
+	t.Parallel()
+
+	src := xgxerror.Adopt(fmt.Errorf("query: %w", context.Canceled), "task", "t1")
+	got := roundTrip(t, src)
+	if got.Error() != src.Error() || xgxerror.CodeOf(got) != "" || !xgxerror.IsInterrupt(got) {
+		t.Fatalf("decoded tag: %q code=%q", got, xgxerror.CodeOf(got))
+	}
+}
+
+// registerOnce keeps registrations idempotent under -count=N.
+var registerOnce sync.Once
+
+type quota struct {
+	Limit int    `json:"limit"`
+	Scope string `json:"scope"`
+}
+
+func TestRoundTrip_RegisteredTypesAndSentinels(t *testing.T) {
+	t.Parallel()
+
+	registerOnce.Do(func() {
+		RegisterType[quota]("jsonx_test.quota")
+		RegisterSentinel("io.EOF", io.EOF)
+	})
+
+	src := xgxerror.TooManyRequests("api").With("quota", quota{Limit: 10, Scope: "user"})
+	wrapped := xgxerror.Internal(io.EOF)
+
+	got := roundTrip(t, src).(xgxerror.Error)
+	if q, ok := xgxerror.FieldOf[quota]("quota").Get(got); !ok || q.Limit != 10 || q.Scope != "user" {
+		t.Fatalf("registered type lost: %+v, %v", q, ok)
+	}
+	if !errors.Is(roundTrip(t, wrapped), io.EOF) {
+		t.Fatalf("registered sentinel identity lost")
+	}
+
+	assertPanics(t, func() { RegisterType[quota]("other") })
+	assertPanics(t, func() { RegisterType[int]("jsonx_test.quota") })
+	assertPanics(t, func() { RegisterType[struct{ X int }]("int") })
+	assertPanics(t, func() { RegisterSentinel("io.EOF", io.ErrUnexpectedEOF) })
+}
+
+func TestRoundTrip_DetailsKeepTypeAndOrder(t *testing.T) {
+	t.Parallel()
+
+	registerOnce.Do(func() {
+		RegisterType[quota]("jsonx_test.quota")
+		RegisterSentinel("io.EOF", io.EOF)
+	})
+
+	src := xgxerror.WithDetail(xgxerror.WithDetail(xgxerror.TooManyRequests("api"), quota{Limit: 10, Scope: "user"}), "hint")
+	src = xgxerror.WithDetail(src, xgxerror.Secret("token"))
+	data, err := Marshal(src)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if strings.Contains(string(data), "token") || !strings.Contains(string(data), `"details":[{"type":"jsonx_test.quota"`) {
+		t.Fatalf("unexpected details encoding: %s", data)
+	}
+
+	got := roundTrip(t, src)
+	if q, ok := xgxerror.DetailOf[quota](got); !ok || q.Limit != 10 {
+		t.Fatalf("registered detail lost: %+v, %v", q, ok)
+	}
+	if d := xgxerror.Inspect(got).Details; len(d) != 3 || d[1] != "hint" || fmt.Sprint(d[2]) != xgxerror.RedactedText {
+		t.Fatalf("details order/redaction: %#v", d)
+	}
+}
+
+func TestRoundTrip_ValidationViolations(t *testing.T) {
+	t.Parallel()
+
+	v := xgxerror.NewValidation()
+	v.Field("items").Index(1).Field("qty").Invalid("max", "limit", 10)
+	got := roundTrip(t, v.Err())
+
+	vs := xgxerror.ViolationsOf(got)
+	if xgxerror.CodeOf(got) != xgxerror.CodeInvalid || len(vs) != 1 {
+		t.Fatalf("violations lost: %v", got)
+	}
+	if vs[0].Field != "items[1].qty" || vs[0].Rule != "max" || vs[0].Params["limit"] != float64(10) {
+		t.Fatalf("violation: %+v", vs[0])
+	}
+}
+
+func TestRoundTrip_NonFiniteFloats(t *testing.T) {
+	t.Parallel()
+
+	src := xgxerror.BadRequest("x").
+		With("nan", math.NaN()).
+		With("inf", math.Inf(1)).
+		With("ninf", float32(math.Inf(-1))).
+		With("one", 1.5)
+	got := roundTrip(t, src).(xgxerror.Error)
+
+	if v, ok := xgxerror.FieldOf[float64]("nan").Get(got); !ok || !math.IsNaN(v) {
+		t.Fatalf("nan: %v, %v", v, ok)
+	}
+	if v, ok := xgxerror.FieldOf[float64]("inf").Get(got); !ok || !math.IsInf(v, 1) {
+		t.Fatalf("+inf: %v, %v", v, ok)
+	}
+	if v, ok := xgxerror.FieldOf[float32]("ninf").Get(got); !ok || !math.IsInf(float64(v), -1) {
+		t.Fatalf("-inf: %v, %v", v, ok)
+	}
+	if v, ok := xgxerror.FieldOf[float64]("one").Get(got); !ok || v != 1.5 {
+		t.Fatalf("finite: %v, %v", v, ok)
+	}
+
+	var out error
+	if err := Unmarshal([]byte(`{"kind":"failure","msg":"x","ctx":[{"key":"f","type":"float64","value":"12"}]}`), &out); err == nil {
+		t.Fatalf("finite float as string should be rejected")
+	}
+}
+
+var registerBrokenOnce sync.Once
+
+// brokenQuota is registered but never marshals.
+type brokenQuota struct{ Limit int }
+
+func (brokenQuota) MarshalJSON() ([]byte, error) { return nil, errors.New("broken") }
+
+func TestRoundTrip_UnmarshalableRegisteredTypeFallsBack(t *testing.T) {
+	t.Parallel()
+
+	registerBrokenOnce.Do(func() { RegisterType[brokenQuota]("jsonx_test.broken") })
+
+	got := roundTrip(t, xgxerror.BadRequest("x").With("q", brokenQuota{Limit: 3}))
+	if s, ok := xgxerror.FieldOf[string]("q").Get(got.(xgxerror.Error)); !ok || s != "{3}" {
+		t.Fatalf("fallback value: %q, %v", s, ok)
+	}
+}
+
+func TestRoundTrip_UnregisteredValuesDecodeGenerically(t *testing.T) {
+	t.Parallel()
+
+	src := xgxerror.BadRequest("x").
+		With("tags", []string{"a", "b"}).
+		With("cause_err", errors.New("inner"))
+	got := roundTrip(t, src).(xgxerror.Error)
+
+	tags, ok := xgxerror.FieldOf[[]any]("tags").Get(got)
+	if !ok || len(tags) != 2 || tags[0] != "a" {
+		t.Fatalf("generic JSON value: %#v, %v", tags, ok)
+	}
+	ce, ok := xgxerror.FieldOf[error]("cause_err").Get(got)
+	if !ok || ce.Error() != "inner" {
+		t.Fatalf("error-valued field: %v, %v", ce, ok)
+	}
+}
+
+func TestEncode_DocumentShape(t *testing.T) {
+	t.Parallel()
+
+	data, err := Marshal(xgxerror.NotFound("user", 7))
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if doc["kind"] != "failure" || doc["code"] != "not_found" || doc["msg"] != "user not found" {
+		t.Fatalf("unexpected header: %s", data)
+	}
+	ctx, _ := doc["ctx"].([]any)
+	if len(ctx) != 2 {
+		t.Fatalf("ctx: want 2 entries, got %s", data)
+	}
+	first, _ := ctx[0].(map[string]any)
+	if first["key"] != "entity" || first["type"] != "string" || first["value"] != "user" {
+		t.Fatalf("ctx[0] = %v", first)
+	}
+}
+
+func TestUnmarshal_RejectsUnknownKindAndType(t *testing.T) {
+	t.Parallel()
+
+	var out error
+	if err := Unmarshal([]byte(`{"kind":"weird","msg":"x"}`), &out); err == nil {
+		t.Fatalf("expected error for unknown kind")
+	}
+	if err := Unmarshal([]byte(`{"kind":"failure","msg":"x","ctx":[{"key":"k","type":"nope","value":1}]}`), &out); err == nil {
+		t.Fatalf("expected error for unknown value type")
+	}
+	if err := Unmarshal([]byte(`{`), &out); err == nil {
+		t.Fatalf("expected syntax error")
+	}
+	if err := Unmarshal([]byte(`null`), nil); err == nil {
+		t.Fatalf("expected error for nil destination")
+	}
+}
+
+func assertPanics(t *testing.T, fn func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic")
+		}
+	}()
+	fn()
+}
+
+func TestEncode_RedactsSensitiveFields(t *testing.T) {
+	t.Parallel()
+
+	src := xgxerror.Unauthorized("denied").
+		Ctx("", "user", "alice", "password", "hunter2", "email", xgxerror.Secret("a@example.com"))
+
+	data, err := Marshal(src)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if s := string(data); strings.Contains(s, "hunter2") || strings.Contains(s, "a@example.com") {
+		t.Fatalf("sensitive value leaked into JSON: %s", s)
+	}
+
+	got := roundTrip(t, src)
+	ctx := got.(xgxerror.Error).Context()
+	if ctx["user"] != "alice" || ctx["password"] != xgxerror.RedactedText || ctx["email"] != xgxerror.RedactedText {
+		t.Fatalf("decoded context = %v", ctx)
+	}
+	if out := fmt.Sprintf("%+v", got); !strings.Contains(out, "email="+xgxerror.RedactedText) {
+		t.Fatalf("decoded error lost redaction marking:\n%s", out)
+	}
+}
+
+func TestRoundTrip_PinnedFields(t *testing.T) {
+	t.Parallel()
+
+	src := xgxerror.WithPinned(xgxerror.BadRequest("x").With("a", 1), "request_id", "r1")
+	data, err := Marshal(src)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if strings.Count(string(data), `"pinned":true`) != 1 {
+		t.Fatalf("unexpected pinned encoding: %s", data)
+	}
+
+	got := xgxerror.From(roundTrip(t, src)).CtxBound("", 1)
+	if n := xgxerror.Inspect(got); len(n.Fields) != 1 || n.Fields[0].Key != "request_id" || len(n.Pinned) != 1 || !n.Pinned[0] {
+		t.Fatalf("pinning lost in round trip: %+v", n)
+	}
+}
@@ -0,0 +1,240 @@
+// values.go — type-tagged encoding of context field values.
+//
+// Each field value is stored with a type tag so that decoding restores the
+// exact Go dynamic type that FieldOf[T].Get expects:
+//
+//	nil                       → "nil"
+//	string, bool              → "string", "bool"
+//	int, int8 … int64         → "int", "int8", … "int64"
+//	uint, uint8 … uint64      → "uint", "uint8", … "uint64"
+//	float32, float64          → "float32", "float64" (NaN and ±Inf as "NaN", "+Inf", "-Inf")
+//	time.Duration             → "duration" (nanoseconds)
+//	time.Time                 → "time" (RFC 3339 with nanoseconds)
+//	error                     → "error" (message; decodes to an opaque error)
+//	sensitive (any type)      → "redacted" (placeholder; decodes to a redacted SecretValue)
+//	registered types          → the registered name (see RegisterType)
+//	xgxerror.Violations       → "violations" (pre-registered)
+//	anything else             → "json" (decodes to generic JSON values)
+//
+// Values that cannot be marshaled, registered types included, fall back to
+// their %v string under "json", so every document Marshal writes decodes.
+package jsonx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	xgxerror "github.com/tuliorib/xgx-error"
+)
+
+var typeRegistry = struct {
+	sync.RWMutex
+	byName map[string]reflect.Type
+	byType map[reflect.Type]string
+}{
+	// Violations (see xgxerror.Validation) are pre-registered so validation
+	// errors keep their typed list; param values decode as generic JSON.
+	byName: map[string]reflect.Type{"violations": reflect.TypeFor[xgxerror.Violations]()},
+	byType: map[reflect.Type]string{reflect.TypeFor[xgxerror.Violations](): "violations"},
+}
+
+// builtinTags are reserved and cannot be used as registered names.
+var builtinTags = map[string]struct{}{
+	"nil": {}, "string": {}, "bool": {},
+	"int": {}, "int8": {}, "int16": {}, "int32": {}, "int64": {},
+	"uint": {}, "uint8": {}, "uint16": {}, "uint32": {}, "uint64": {},
+	"float32": {}, "float64": {},
+	"duration": {}, "time": {}, "error": {}, "json": {}, "redacted": {},
+}
+
+// RegisterType registers T under name so that field values of type T decode
+// back to T (via encoding/json) instead of generic JSON values.
+//
+// Names must be unique and must not collide with built-in tags. RegisterType
+// panics on duplicates, like gob.Register; call it from init.
+func RegisterType[T any](name string) {
+	t := reflect.TypeFor[T]()
+	if name == "" {
+		panic("jsonx: RegisterType requires a non-empty name")
+	}
+	if _, reserved := builtinTags[name]; reserved {
+		panic(fmt.Sprintf("jsonx: type name %q is reserved", name))
+	}
+	typeRegistry.Lock()
+	defer typeRegistry.Unlock()
+	if _, dup := typeRegistry.byName[name]; dup {
+		panic(fmt.Sprintf("jsonx: type name %q already registered", name))
+	}
+	if prev, dup := typeRegistry.byType[t]; dup {
+		panic(fmt.Sprintf("jsonx: type %v already registered as %q", t, prev))
+	}
+	typeRegistry.byName[name] = t
+	typeRegistry.byType[t] = name
+}
+
+func registeredName(t reflect.Type) (string, bool) {
+	typeRegistry.RLock()
+	defer typeRegistry.RUnlock()
+	name, ok := typeRegistry.byType[t]
+	return name, ok
+}
+
+func registeredType(name string) (reflect.Type, bool) {
+	typeRegistry.RLock()
+	defer typeRegistry.RUnlock()
+	t, ok := typeRegistry.byName[name]
+	return t, ok
+}
+
+// encodeValue returns the type tag and JSON payload for v.
+func encodeValue(v any) (string, json.RawMessage) {
+	switch x := v.(type) {
+	case nil:
+		return "nil", json.RawMessage("null")
+	case string:
+		return "string", mustJSON(x)
+	case bool:
+		return "bool", mustJSON(x)
+	case int:
+		return "int", mustJSON(x)
+	case int8:
+		return "int8", mustJSON(x)
+	case int16:
+		return "int16", mustJSON(x)
+	case int32:
+		return "int32", mustJSON(x)
+	case int64:
+		return "int64", mustJSON(x)
+	case uint:
+		return "uint", mustJSON(x)
+	case uint8:
+		return "uint8", mustJSON(x)
+	case uint16:
+		return "uint16", mustJSON(x)
+	case uint32:
+		return "uint32", mustJSON(x)
+	case uint64:
+		return "uint64", mustJSON(x)
+	case float32:
+		return "float32", floatJSON(x)
+	case float64:
+		return "float64", floatJSON(x)
+	case time.Duration:
+		return "duration", mustJSON(int64(x))
+	case time.Time:
+		return "time", mustJSON(x.Format(time.RFC3339Nano))
+	}
+	if name, ok := registeredName(reflect.TypeOf(v)); ok {
+		// A payload that fails to marshal could never decode back to the
+		// registered type; let it fall through to the %v fallback instead.
+		if b, err := json.Marshal(v); err == nil {
+			return name, b
+		}
+	}
+	if e, ok := v.(error); ok {
+		return "error", mustJSON(e.Error())
+	}
+	return "json", mustJSON(v)
+}
+
+// decodeValue restores a field value from its type tag and JSON payload.
+func decodeValue(typ string, raw json.RawMessage) (any, error) {
+	switch typ {
+	case "nil":
+		return nil, nil
+	case "string":
+		return decodeAs[string](raw)
+	case "bool":
+		return decodeAs[bool](raw)
+	case "int":
+		return decodeAs[int](raw)
+	case "int8":
+		return decodeAs[int8](raw)
+	case "int16":
+		return decodeAs[int16](raw)
+	case "int32":
+		return decodeAs[int32](raw)
+	case "int64":
+		return decodeAs[int64](raw)
+	case "uint":
+		return decodeAs[uint](raw)
+	case "uint8":
+		return decodeAs[uint8](raw)
+	case "uint16":
+		return decodeAs[uint16](raw)
+	case "uint32":
+		return decodeAs[uint32](raw)
+	case "uint64":
+		return decodeAs[uint64](raw)
+	case "float32":
+		return decodeFloat[float32](raw)
+	case "float64":
+		return decodeFloat[float64](raw)
+	case "duration":
+		ns, err := decodeAs[int64](raw)
+		return time.Duration(ns), err
+	case "time":
+		s, err := decodeAs[string](raw)
+		if err != nil {
+			return nil, err
+		}
+		return time.Parse(time.RFC3339Nano, s)
+	case "error":
+		s, err := decodeAs[string](raw)
+		if err != nil {
+			return nil, err
+		}
+		return errors.New(s), nil
+	case "redacted":
+		return xgxerror.Secret(nil), nil
+	case "json":
+		if isNull(raw) {
+			return nil, nil
+		}
+		var v any
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	}
+	if t, ok := registeredType(typ); ok {
+		p := reflect.New(t)
+		if err := json.Unmarshal(raw, p.Interface()); err != nil {
+			return nil, err
+		}
+		return p.Elem().Interface(), nil
+	}
+	return nil, fmt.Errorf("unknown value type %q (missing RegisterType?)", typ)
+}
+
+func decodeAs[T any](raw json.RawMessage) (T, error) {
+	var v T
+	err := json.Unmarshal(raw, &v)
+	return v, err
+}
+
+// floatJSON encodes x, writing NaN and ±Inf (which JSON numbers cannot hold)
+// as the strings "NaN", "+Inf" and "-Inf".
+func floatJSON[T float32 | float64](x T) json.RawMessage {
+	if f := float64(x); math.IsNaN(f) || math.IsInf(f, 0) {
+		return mustJSON(strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	return mustJSON(x)
+}
+
+// decodeFloat reads a JSON number or one of floatJSON's non-finite strings.
+func decodeFloat[T float32 | float64](raw json.RawMessage) (T, error) {
+	var s string
+	if json.Unmarshal(raw, &s) != nil {
+		return decodeAs[T](raw)
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || !(math.IsNaN(f) || math.IsInf(f, 0)) {
+		return 0, fmt.Errorf("invalid float %q", s)
+	}
+	return T(f), nil
+}
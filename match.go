@@ -0,0 +1,487 @@
+// match.go — composable, serializable matchers over error graphs.
+//
+// A Matcher tests ONE node; Find, FindAll and Matches apply it to every node
+// Walk visits. Combinators (And, Or, Not) therefore combine predicates on the
+// same node: And(CodeIn(CodeNotFound), HasField("tenant")) needs a single node
+// with both, not one of each somewhere in the graph.
+//
+// Serialization: String renders a matcher as a stable expression and
+// ParseMatcher reads it back, so alert/routing rules can live in config:
+//
+//	and(code_in("unavailable", "timeout"), not(field_equals("tenant", "internal")))
+//
+// Grammar: name "(" [arg {"," arg}] ")", where an arg is a nested matcher, a
+// Go-quoted string, an integer, a float or true/false. TypeOf and IsA depend
+// on Go values and render for display only; ParseMatcher rejects them.
+// field_equals compares numbers by value, so a rule written as 3 matches a
+// field stored as int64(3) or uint8(3).
+package xgxerror
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Matcher reports whether a single error node matches.
+type Matcher interface {
+	Match(node error) bool
+	// String renders the matcher expression (see ParseMatcher).
+	String() string
+}
+
+// matcher is the implementation behind every constructor in this file.
+type matcher struct {
+	expr string
+	fn   func(error) bool
+}
+
+func (m matcher) Match(node error) bool { return node != nil && m.fn(node) }
+func (m matcher) String() string        { return m.expr }
+
+// Find returns the first node (Walk order) that m matches, or nil.
+func Find(err error, m Matcher) error {
+	var out error
+	Walk(err, func(e error) bool {
+		if m.Match(e) {
+			out = e
+			return false
+		}
+		return true
+	})
+	return out
+}
+
+// FindAll returns every node that m matches, in Walk order.
+func FindAll(err error, m Matcher) []error {
+	var out []error
+	Walk(err, func(e error) bool {
+		if m.Match(e) {
+			out = append(out, e)
+		}
+		return true
+	})
+	return out
+}
+
+// Matches reports whether any node in err's graph matches m.
+func Matches(err error, m Matcher) bool { return Find(err, m) != nil }
+
+// -----------------------------------------------------------------------------
+// Leaf matchers
+// -----------------------------------------------------------------------------
+
+// CodeIn matches nodes whose code IS-A any of codes (hierarchy-aware, like
+// HasCode). Join containers never match; their children are tested instead.
+func CodeIn(codes ...Code) Matcher {
+	codes = append([]Code(nil), codes...)
+	args := make([]string, len(codes))
+	for i, c := range codes {
+		args[i] = strconv.Quote(string(c))
+	}
+	return matcher{expr: call("code_in", args...), fn: func(e error) bool {
+		if _, isJoin := e.(*multi); isJoin {
+			return false
+		}
+		c, ok := e.(coder)
+		if !ok {
+			return false
+		}
+		for _, want := range codes {
+			if c.CodeVal().IsA(want) {
+				return true
+			}
+		}
+		return false
+	}}
+}
+
+// HasField matches nodes carrying a context field named key.
+func HasField(key string) Matcher {
+	return matcher{expr: call("has_field", strconv.Quote(key)), fn: func(e error) bool {
+		_, ok := nodeField(e, key)
+		return ok
+	}}
+}
+
+// FieldEquals matches nodes whose newest value for key equals val. Numbers of
+// the predeclared int, uint and float types compare by value, so a stored
+// int64(3) matches 3 and 3.0 alike (ParseMatcher reads "3" as int and "3.0"
+// as float64). Other values must be == with the same dynamic type. Secret
+// values never match.
+func FieldEquals(key string, val any) Matcher {
+	return matcher{expr: call("field_equals", strconv.Quote(key), literal(val)), fn: func(e error) bool {
+		got, ok := nodeField(e, key)
+		if _, secret := got.(SecretValue); !ok || secret {
+			return false
+		}
+		return numericEqual(got, val) || safeEqual(got, val)
+	}}
+}
+
+// MsgContains matches nodes whose raw message (see Node.Msg) contains sub.
+func MsgContains(sub string) Matcher {
+	return matcher{expr: call("msg_contains", strconv.Quote(sub)), fn: func(e error) bool {
+		if _, isJoin := e.(*multi); isJoin {
+			return false
+		}
+		return strings.Contains(Inspect(e).Msg, sub)
+	}}
+}
+
+// TypeOf matches nodes whose dynamic type is (or implements) T.
+func TypeOf[T any]() Matcher {
+	name := reflect.TypeFor[T]().String()
+	return matcher{expr: call("type_of", strconv.Quote(name)), fn: func(e error) bool {
+		_, ok := e.(T)
+		return ok
+	}}
+}
+
+// IsA matches nodes that equal target or report Is(target) themselves,
+// without unwrapping (Find/Matches do the traversal). Code sentinels work:
+// IsA(CodeNotFound.Sentinel()).
+func IsA(target error) Matcher {
+	return matcher{expr: call("is_a", strconv.Quote(fmt.Sprint(target))), fn: func(e error) bool {
+		if target == nil {
+			return false
+		}
+		if safeEqual(e, target) {
+			return true
+		}
+		x, ok := e.(interface{ Is(error) bool })
+		return ok && x.Is(target)
+	}}
+}
+
+// -----------------------------------------------------------------------------
+// Combinators
+// -----------------------------------------------------------------------------
+
+// And matches when every m matches the same node (true for no matchers).
+func And(ms ...Matcher) Matcher {
+	ms = append([]Matcher(nil), ms...)
+	return matcher{expr: call("and", exprs(ms)...), fn: func(e error) bool {
+		for _, m := range ms {
+			if !m.Match(e) {
+				return false
+			}
+		}
+		return true
+	}}
+}
+
+// Or matches when any m matches the node (false for no matchers).
+func Or(ms ...Matcher) Matcher {
+	ms = append([]Matcher(nil), ms...)
+	return matcher{expr: call("or", exprs(ms)...), fn: func(e error) bool {
+		for _, m := range ms {
+			if m.Match(e) {
+				return true
+			}
+		}
+		return false
+	}}
+}
+
+// Not matches nodes that m does not match. Matches(err, Not(m)) is therefore
+// "some node does not match", not "no node matches"; use !Matches(err, m).
+func Not(m Matcher) Matcher {
+	return matcher{expr: call("not", m.String()), fn: func(e error) bool { return !m.Match(e) }}
+}
+
+// -----------------------------------------------------------------------------
+// Parsing
+// -----------------------------------------------------------------------------
+
+// ParseMatcher parses an expression produced by Matcher.String. Only the
+// serializable matchers are accepted: code_in, has_field, field_equals,
+// msg_contains, and, or, not.
+func ParseMatcher(s string) (Matcher, error) {
+	p := &matchParser{s: s}
+	m, err := p.matcher()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.i != len(p.s) {
+		return nil, p.errorf("unexpected trailing input")
+	}
+	return m, nil
+}
+
+type matchParser struct {
+	s string
+	i int
+}
+
+func (p *matchParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("xgxerror: matcher at offset %d: %s", p.i, fmt.Sprintf(format, args...))
+}
+
+func (p *matchParser) skipSpace() {
+	for p.i < len(p.s) && (p.s[p.i] == ' ' || p.s[p.i] == '\t' || p.s[p.i] == '\n' || p.s[p.i] == '\r') {
+		p.i++
+	}
+}
+
+// matcher parses name "(" args ")".
+func (p *matchParser) matcher() (Matcher, error) {
+	p.skipSpace()
+	start := p.i
+	for p.i < len(p.s) && (p.s[p.i] == '_' || p.s[p.i] >= 'a' && p.s[p.i] <= 'z') {
+		p.i++
+	}
+	name := p.s[start:p.i]
+	if name == "" {
+		return nil, p.errorf("expected matcher name")
+	}
+	p.skipSpace()
+	if p.i >= len(p.s) || p.s[p.i] != '(' {
+		return nil, p.errorf("expected '(' after %s", name)
+	}
+	p.i++
+
+	var (
+		subs []Matcher
+		lits []any
+	)
+	p.skipSpace()
+	for p.i < len(p.s) && p.s[p.i] != ')' {
+		if len(subs)+len(lits) > 0 {
+			if p.s[p.i] != ',' {
+				return nil, p.errorf("expected ',' or ')'")
+			}
+			p.i++
+			p.skipSpace()
+		}
+		if p.i < len(p.s) && p.s[p.i] >= 'a' && p.s[p.i] <= 'z' && !p.atBool() {
+			m, err := p.matcher()
+			if err != nil {
+				return nil, err
+			}
+			subs = append(subs, m)
+		} else {
+			v, err := p.literal()
+			if err != nil {
+				return nil, err
+			}
+			lits = append(lits, v)
+		}
+		p.skipSpace()
+	}
+	if p.i >= len(p.s) {
+		return nil, p.errorf("unterminated %s(", name)
+	}
+	p.i++ // ')'
+	return buildMatcher(name, subs, lits, p)
+}
+
+// atBool reports whether the input continues with a true/false literal.
+func (p *matchParser) atBool() bool {
+	for _, b := range []string{"true", "false"} {
+		if strings.HasPrefix(p.s[p.i:], b) {
+			rest := p.s[p.i+len(b):]
+			if rest == "" || !(rest[0] == '_' || rest[0] == '(' || rest[0] >= 'a' && rest[0] <= 'z') {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// literal parses a quoted string, bool, integer or float.
+func (p *matchParser) literal() (any, error) {
+	if p.i < len(p.s) && p.s[p.i] == '"' {
+		q, err := strconv.QuotedPrefix(p.s[p.i:])
+		if err != nil {
+			return nil, p.errorf("bad string literal")
+		}
+		p.i += len(q)
+		return strconv.Unquote(q)
+	}
+	start := p.i
+	for p.i < len(p.s) && !strings.ContainsRune(",) \t\r\n", rune(p.s[p.i])) {
+		p.i++
+	}
+	tok := p.s[start:p.i]
+	switch tok {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if n, err := strconv.Atoi(tok); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(tok, 64); err == nil {
+		return f, nil
+	}
+	return nil, p.errorf("bad literal %q", tok)
+}
+
+func buildMatcher(name string, subs []Matcher, lits []any, p *matchParser) (Matcher, error) {
+	strs := make([]string, 0, len(lits))
+	for _, l := range lits {
+		if s, ok := l.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	onlyStrings := len(subs) == 0 && len(strs) == len(lits)
+	switch name {
+	case "and", "or", "not":
+		if len(lits) > 0 {
+			return nil, p.errorf("%s takes matchers only", name)
+		}
+		switch {
+		case name == "and":
+			return And(subs...), nil
+		case name == "or":
+			return Or(subs...), nil
+		case len(subs) == 1:
+			return Not(subs[0]), nil
+		}
+		return nil, p.errorf("not takes exactly one matcher")
+	case "code_in":
+		if !onlyStrings {
+			return nil, p.errorf("code_in takes strings only")
+		}
+		codes := make([]Code, len(strs))
+		for i, s := range strs {
+			codes[i] = Code(s)
+		}
+		return CodeIn(codes...), nil
+	case "has_field", "msg_contains":
+		if !onlyStrings || len(strs) != 1 {
+			return nil, p.errorf("%s takes one string", name)
+		}
+		if name == "has_field" {
+			return HasField(strs[0]), nil
+		}
+		return MsgContains(strs[0]), nil
+	case "field_equals":
+		if len(subs) != 0 || len(lits) != 2 {
+			return nil, p.errorf("field_equals takes a key and a value")
+		}
+		key, ok := lits[0].(string)
+		if !ok {
+			return nil, p.errorf("field_equals key must be a string")
+		}
+		return FieldEquals(key, lits[1]), nil
+	case "type_of", "is_a":
+		return nil, p.errorf("%s cannot be parsed; build it in Go", name)
+	}
+	return nil, p.errorf("unknown matcher %q", name)
+}
+
+// -----------------------------------------------------------------------------
+// Helpers
+// -----------------------------------------------------------------------------
+
+// nodeField returns the newest value for key on a single node.
+func nodeField(e error, key string) (any, bool) {
+	if lk, ok := e.(fieldLookup); ok {
+		return lk.lookupFieldLast(key)
+	}
+	if xe, ok := e.(Error); ok {
+		v, ok := xe.Context()[key]
+		return v, ok
+	}
+	return nil, false
+}
+
+// safeEqual reports a == b without panicking on non-comparable dynamic
+// values (which never compare equal here).
+func safeEqual(a, b any) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	return va.Type() == vb.Type() && va.Comparable() && vb.Comparable() && va.Equal(vb)
+}
+
+// numericEqual reports whether a and b are both predeclared numbers (ints,
+// uints, floats) with the same value. Conversions are exact: 2^53+1 does not
+// equal the float64 nearest to it, and NaN equals nothing.
+func numericEqual(a, b any) bool {
+	va, ok := numericValue(a)
+	if !ok {
+		return false
+	}
+	vb, ok := numericValue(b)
+	if !ok {
+		return false
+	}
+	switch {
+	case va.Kind() == reflect.Float64 || vb.Kind() == reflect.Float64:
+		if va.Kind() != reflect.Float64 {
+			va, vb = vb, va
+		}
+		f := va.Float()
+		switch vb.Kind() {
+		case reflect.Float64:
+			return f == vb.Float()
+		case reflect.Int64:
+			return f == math.Trunc(f) && f >= -(1<<63) && f < 1<<63 && int64(f) == vb.Int()
+		default:
+			return f == math.Trunc(f) && f >= 0 && f < 1<<64 && uint64(f) == vb.Uint()
+		}
+	case va.Kind() == vb.Kind():
+		return va.Equal(vb)
+	case va.Kind() == reflect.Int64: // int vs uint
+		return va.Int() >= 0 && uint64(va.Int()) == vb.Uint()
+	default:
+		return vb.Int() >= 0 && uint64(vb.Int()) == va.Uint()
+	}
+}
+
+// numericValue widens a predeclared number to an int64, uint64 or float64
+// reflect.Value. Named types (time.Duration, enums) are not numbers here.
+func numericValue(x any) (reflect.Value, bool) {
+	if x == nil {
+		return reflect.Value{}, false
+	}
+	v := reflect.ValueOf(x)
+	if v.Type().PkgPath() != "" {
+		return reflect.Value{}, false
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflect.ValueOf(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return reflect.ValueOf(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return reflect.ValueOf(v.Float()), true
+	}
+	return reflect.Value{}, false
+}
+
+func call(name string, args ...string) string {
+	return name + "(" + strings.Join(args, ", ") + ")"
+}
+
+func exprs(ms []Matcher) []string {
+	out := make([]string, len(ms))
+	for i, m := range ms {
+		out[i] = m.String()
+	}
+	return out
+}
+
+// literal renders val so ParseMatcher reads back the same value where it
+// can (strings, bools, ints, float64); other types render with %v.
+func literal(val any) string {
+	switch v := val.(type) {
+	case string:
+		return strconv.Quote(v)
+	case float64:
+		s := strconv.FormatFloat(v, 'g', -1, 64)
+		if !strings.ContainsAny(s, ".eEnNI") { // keep it a float on re-parse
+			s += ".0"
+		}
+		return s
+	}
+	return fmt.Sprint(val)
+}
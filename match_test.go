@@ -0,0 +1,190 @@
+// match_test.go — verification of composable error matchers.
+package xgxerror
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMatchers_Leaves(t *testing.T) {
+	t.Parallel()
+
+	nf := NotFound("user", 7).With("tenant", "acme").With("attempt", 3)
+	cases := []struct {
+		name string
+		m    Matcher
+		want bool
+	}{
+		{"code_in", CodeIn(CodeUnavailable, CodeNotFound), true},
+		{"code_in_miss", CodeIn(CodeInvalid), false},
+		{"has_field", HasField("tenant"), true},
+		{"has_field_miss", HasField("region"), false},
+		{"field_equals", FieldEquals("tenant", "acme"), true},
+		{"field_equals_numeric", FieldEquals("attempt", int64(3)), true},
+		{"field_equals_type", FieldEquals("attempt", "3"), false},
+		{"field_equals_noncomparable", FieldEquals("tenant", []string{"acme"}), false},
+		{"msg_contains", MsgContains("not found"), true},
+		{"type_of", TypeOf[*failureErr](), true},
+		{"type_of_iface", TypeOf[Error](), true},
+		{"is_a_sentinel", IsA(CodeNotFound.Sentinel()), true},
+		{"is_a_self", IsA(nf), true},
+		{"is_a_other", IsA(CodeConflict.Sentinel()), false},
+	}
+	for _, tc := range cases {
+		if got := tc.m.Match(nf); got != tc.want {
+			t.Fatalf("%s: %s.Match = %v, want %v", tc.name, tc.m, got, tc.want)
+		}
+	}
+	if HasField("tenant").Match(nil) {
+		t.Fatalf("nil never matches")
+	}
+	if FieldEquals("k", "s").Match(New("x", "k", Secret("s"))) {
+		t.Fatalf("secret values must not match")
+	}
+}
+
+func TestMatchers_CombineOnSameNode(t *testing.T) {
+	t.Parallel()
+
+	err := Join(NotFound("user", 1), New("boom", "tenant", "acme"))
+	both := And(CodeIn(CodeNotFound), HasField("tenant"))
+	if Matches(err, both) {
+		t.Fatalf("And must require both predicates on ONE node")
+	}
+	if !Matches(err, Or(CodeIn(CodeNotFound), HasField("tenant"))) {
+		t.Fatalf("Or should match")
+	}
+	if !Matches(Join(NotFound("user", 1).With("tenant", "acme")), both) {
+		t.Fatalf("And should match a node carrying both")
+	}
+	if !And().Match(err) || Or().Match(err) {
+		t.Fatalf("empty And/Or identities")
+	}
+	if Not(HasField("tenant")).Match(New("x", "tenant", 1)) {
+		t.Fatalf("Not should negate")
+	}
+}
+
+func TestFind_TraversesJoinAndForeignWrappers(t *testing.T) {
+	t.Parallel()
+
+	deep := Wrap(Unavailable("db").With("tenant", "acme"), "load")
+	err := Join(
+		Invalid("email", "format"),
+		fmt.Errorf("repo: %w", deep),
+		Timeout(0),
+	)
+	if got := Find(err, FieldEquals("tenant", "acme")); got != deep {
+		t.Fatalf("Find = %v, want %v", got, deep)
+	}
+	all := FindAll(err, CodeIn(CodeUnavailable, CodeTimeout))
+	if len(all) != 2 || all[0] != deep {
+		t.Fatalf("FindAll = %v", all)
+	}
+	if CodeIn(CodeInternal).Match(err) {
+		t.Fatalf("join containers must not match code_in on their own")
+	}
+	if Find(err, MsgContains("nope")) != nil || Matches(nil, And()) {
+		t.Fatalf("no match should return nil/false")
+	}
+	if !Matches(err, IsA(CodeTimeout.Sentinel())) {
+		t.Fatalf("IsA with a code sentinel should find the timeout")
+	}
+}
+
+func TestParseMatcher_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ms := []Matcher{
+		CodeIn(CodeUnavailable, "timeout"),
+		And(CodeIn(CodeNotFound), Not(FieldEquals("tenant", "internal"))),
+		Or(HasField("k"), MsgContains(`say "hi", then )`)),
+		FieldEquals("n", 3),
+		FieldEquals("ratio", 2.0),
+		FieldEquals("ratio", 0.25),
+		FieldEquals("ok", true),
+		And(),
+	}
+	for _, m := range ms {
+		p, err := ParseMatcher(m.String())
+		if err != nil {
+			t.Fatalf("ParseMatcher(%s): %v", m, err)
+		}
+		if p.String() != m.String() {
+			t.Fatalf("round trip: %s != %s", p, m)
+		}
+	}
+
+	p, err := ParseMatcher(` and( code_in("unavailable"),field_equals("n", 3) ) `)
+	if err != nil {
+		t.Fatalf("whitespace should be accepted: %v", err)
+	}
+	if !Matches(Wrap(Unavailable("db"), "x").With("n", 3), p) || !Matches(Unavailable("db").With("n", 3.0), p) ||
+		Matches(Unavailable("db").With("n", 3.5), p) {
+		t.Fatalf("parsed numeric literals should compare by value")
+	}
+	// A round-tripped int64 rule still matches int64 fields.
+	p, err = ParseMatcher(FieldEquals("n", int64(1)<<40).String())
+	if err != nil || !Matches(New("x", "n", int64(1)<<40), p) {
+		t.Fatalf("int64 round trip: %v", err)
+	}
+}
+
+func TestFieldEquals_NumbersCompareByValue(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		stored, want any
+		match        bool
+	}{
+		{3, int64(3), true},
+		{uint8(3), 3, true},
+		{int32(-1), uint(math.MaxUint64), false},
+		{uint64(math.MaxUint64), -1, false},
+		{3, 3.0, true},
+		{float32(0.5), 0.5, true},
+		{3, 3.5, false},
+		{int64(1<<53 + 1), float64(1 << 53), false},
+		{uint64(1 << 63), float64(1 << 63), true},
+		{math.NaN(), math.NaN(), false},
+		{time.Duration(3), 3, false}, // named types are not plain numbers
+		{"3", 3, false},
+	}
+	for _, tc := range cases {
+		got := FieldEquals("n", tc.want).Match(New("x", "n", tc.stored))
+		if got != tc.match {
+			t.Fatalf("stored %T(%v) vs %T(%v): got %v, want %v", tc.stored, tc.stored, tc.want, tc.want, got, tc.match)
+		}
+	}
+}
+
+func TestParseMatcher_Errors(t *testing.T) {
+	t.Parallel()
+
+	bad := []string{
+		"",
+		"code_in",
+		`code_in("x"`,
+		`code_in("x") extra`,
+		`code_in(1)`,
+		`has_field("a", "b")`,
+		`field_equals(1, 2)`,
+		`not(has_field("a"), has_field("b"))`,
+		`and("x")`,
+		`field_equals("k", bogus)`,
+		`nope("x")`,
+		`type_of("*xgxerror.failureErr")`,
+		`is_a("x")`,
+	}
+	for _, s := range bad {
+		if m, err := ParseMatcher(s); err == nil {
+			t.Fatalf("ParseMatcher(%q) = %s, want error", s, m)
+		}
+	}
+	if _, err := ParseMatcher("is_a(\"x\")"); err == nil || errors.Unwrap(err) != nil {
+		t.Fatalf("parse errors should be plain errors: %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+// path.go — positional traversal: where in the graph a node sits.
+//
+// Walk visits nodes but says nothing about their position. WalkPath reports,
+// for every node, a Path: its ancestors from the root and the child index taken
+// at each multi-unwrap (Join) ancestor. That answers "which sub-task of the
+// batch failed" (Branches) and "is this code at the top or deep in a cause
+// chain" (Depth):
+//
+//	if p, ok := xgxerror.Locate(err, xgxerror.CodeUnavailable.Sentinel()); ok && p.Depth() <= 1 {
+//	    severity = "page"
+//	}
+//
+// Semantics:
+//   - Same nodes and order as Walk (pre-order, distinct, cycle-safe). A node
+//     reachable along several routes is reported once, with the first Path.
+//   - The Path passed to the callback is reused; Clone it to keep it.
+package xgxerror
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Path locates a node inside an error graph: one Step per ancestor, from the
+// root down to the node's parent. The root's Path is empty.
+type Path []Step
+
+// Step is one edge taken from an ancestor towards a node.
+type Step struct {
+	Err   error // the ancestor
+	Index int   // child index for multi-unwrap (Join) ancestors; -1 for Unwrap() error
+}
+
+// Depth reports how many edges separate the node from the root.
+func (p Path) Depth() int { return len(p) }
+
+// Parent returns the node's direct parent, or nil for the root.
+func (p Path) Parent() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return p[len(p)-1].Err
+}
+
+// Branches returns the child indices taken at multi-unwrap ancestors, outermost
+// first — e.g. [1 0] for the first sub-error of the second branch of a batch.
+func (p Path) Branches() []int {
+	var out []int
+	for _, s := range p {
+		if s.Index >= 0 {
+			out = append(out, s.Index)
+		}
+	}
+	return out
+}
+
+// Clone returns a copy of p that stays valid after iteration continues.
+func (p Path) Clone() Path {
+	if p == nil {
+		return nil
+	}
+	return append(Path(nil), p...)
+}
+
+// String renders p as "$" followed by ".cause" per single unwrap and "[i]"
+// per multi-unwrap branch, e.g. "$[1].cause".
+func (p Path) String() string {
+	var b strings.Builder
+	b.WriteByte('$')
+	for _, s := range p {
+		if s.Index < 0 {
+			b.WriteString(".cause")
+			continue
+		}
+		b.WriteByte('[')
+		b.WriteString(strconv.Itoa(s.Index))
+		b.WriteByte(']')
+	}
+	return b.String()
+}
+
+// WalkPath is Walk with positional information: visit receives each distinct
+// node together with its Path (empty for the root). Returning false stops the
+// traversal. It is safe on cycles and nil is a no-op.
+func WalkPath(err error, visit func(p Path, e error) bool) {
+	if err == nil || visit == nil {
+		return
+	}
+	// Nodes are marked seen when their parent is expanded (as in Walk), so
+	// both traversals visit the same nodes in the same order.
+	const maxDepth = 1 << 12
+	type frame struct {
+		e     error
+		depth int  // len(Path) of e
+		step  Step // edge from e's parent (unused for the root)
+	}
+
+	stack := make([]frame, 0, 8)
+	path := make(Path, 0, 8)
+	seenErr := make(map[error]struct{}, 16)
+	seenPtr := make(map[uintptr]struct{}, 16)
+
+	stack = append(stack, frame{e: err})
+	_ = markSeen(err, seenErr, seenPtr)
+
+	for len(stack) > 0 && len(stack) < maxDepth {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		// Pre-order DFS: everything above cur.depth on the path is an ancestor.
+		path = path[:max(cur.depth-1, 0)]
+		if cur.depth > 0 {
+			path = append(path, cur.step)
+		}
+		if !visit(path, cur.e) {
+			return
+		}
+
+		if m, ok := cur.e.(multiUnwrapper); ok {
+			kids := m.Unwrap()
+			for i := len(kids) - 1; i >= 0; i-- {
+				if c := kids[i]; c != nil && markSeen(c, seenErr, seenPtr) {
+					stack = append(stack, frame{e: c, depth: cur.depth + 1, step: Step{Err: cur.e, Index: i}})
+				}
+			}
+			continue
+		}
+		if s, ok := cur.e.(singleUnwrapper); ok {
+			if u := s.Unwrap(); u != nil && markSeen(u, seenErr, seenPtr) {
+				stack = append(stack, frame{e: u, depth: cur.depth + 1, step: Step{Err: cur.e, Index: -1}})
+			}
+		}
+	}
+}
+
+// Locate returns the Path of the first node (Walk order) that is target, or
+// that reports Is(target) itself, so code sentinels work. The returned Path is
+// owned by the caller.
+func Locate(err, target error) (Path, bool) {
+	if target == nil {
+		return nil, false
+	}
+	m := IsA(target)
+	var (
+		out   Path
+		found bool
+	)
+	WalkPath(err, func(p Path, e error) bool {
+		if m.Match(e) {
+			out, found = p.Clone(), true
+			return false
+		}
+		return true
+	})
+	return out, found
+}
@@ -0,0 +1,81 @@
+// path_test.go — verification of positional traversal.
+package xgxerror
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestWalkPath_BatchBranches(t *testing.T) {
+	t.Parallel()
+
+	batch := Join(
+		Wrap(errors.New("ok?"), "task 0"),
+		Join(Timeout(0), fmt.Errorf("task 1.1: %w", io.EOF)),
+	)
+
+	got := map[error]string{}
+	WalkPath(batch, func(p Path, e error) bool {
+		got[e] = p.String()
+		return true
+	})
+	if got[batch] != "$" || got[io.EOF] != "$[1][1].cause" {
+		t.Fatalf("paths: %v", got)
+	}
+
+	var visited int
+	WalkPath(batch, func(Path, error) bool { visited++; return visited < 2 })
+	if visited != 2 {
+		t.Fatalf("early stop: visited %d", visited)
+	}
+	WalkPath(nil, func(Path, error) bool { t.Fatalf("nil visited"); return true })
+	WalkPath(batch, nil) // no-op
+}
+
+func TestWalkPath_SharedNodeReportedOnce(t *testing.T) {
+	t.Parallel()
+
+	shared := errors.New("shared")
+	err := Join(fmt.Errorf("a: %w", shared), shared)
+
+	var paths []string
+	WalkPath(err, func(p Path, e error) bool {
+		if e == shared {
+			paths = append(paths, p.String())
+		}
+		return true
+	})
+	// Walk marks children when their parent expands, so the direct branch wins.
+	if !slices.Equal(paths, []string{"$[1]"}) {
+		t.Fatalf("shared node paths: %v", paths)
+	}
+}
+
+func TestLocate_TopVersusDeep(t *testing.T) {
+	t.Parallel()
+
+	top := Wrap(Unavailable("db"), "load")
+	p, ok := Locate(top, CodeUnavailable.Sentinel())
+	if !ok || p.Depth() != 0 {
+		t.Fatalf("top-level code: ok=%v path=%v", ok, p)
+	}
+
+	deep := Internal(fmt.Errorf("repo: %w", Join(BadRequest("x"), Unavailable("cache"))))
+	p, ok = Locate(deep, CodeUnavailable.Sentinel())
+	if !ok || p.String() != "$.cause.cause[1]" || !slices.Equal(p.Branches(), []int{1}) {
+		t.Fatalf("deep code: ok=%v path=%v", ok, p)
+	}
+	if _, ok := p.Parent().(*multi); !ok {
+		t.Fatalf("parent should be the join: %T", p.Parent())
+	}
+
+	if p, ok := Locate(deep, io.EOF); ok || p != nil {
+		t.Fatalf("absent target: %v %v", p, ok)
+	}
+	if _, ok := Locate(deep, nil); ok {
+		t.Fatalf("nil target must not be found")
+	}
+}
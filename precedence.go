@@ -0,0 +1,128 @@
+// precedence.go — policy-based dominant code for error graphs.
+//
+// CodeOf returns the FIRST code in DFS order, so the code of a Join depends on
+// child order: CodeOf(Join(Invalid(...), Internal(...))) is "invalid" although
+// an internal failure happened. DominantCode instead ranks the code of every
+// join branch by a precedence list and returns the most severe one.
+//
+// Semantics:
+//   - Precedence applies ACROSS join branches, never along a cause chain: each
+//     branch contributes its OUTERMOST code (what CodeOf reports for that
+//     branch), so Recode(Internal(Unavailable("db")), CodeNotFound) is
+//     "not_found". Codes below it were deliberately reclassified.
+//   - Codeless wrappers are looked through; joins met on the way fan out into
+//     branches of their own.
+//   - A branch's code (native or not) is ranked by the first precedence entry
+//     it IS-A ("unavailable.db" ranks as "unavailable"); the precise code is
+//     returned.
+//   - A branch ending in a foreign context.Canceled / context.DeadlineExceeded
+//     counts as CodeInterrupt, matching IsInterrupt.
+//   - Codes missing from the list rank below every listed code; ties (and
+//     unlisted codes) resolve to the first in DFS order.
+//   - Join containers report DominantCode under DefaultPrecedence from their
+//     own CodeVal.
+package xgxerror
+
+import "context"
+
+// defaultPrecedence orders built-in codes from most to least dominant:
+// programming bugs, server-side failures, cancellation (below them, so a
+// failure that canceled its siblings still wins), throttling, and finally
+// client errors.
+var defaultPrecedence = []Code{
+	CodeDefect,
+	CodeInternal,
+	CodeUnavailable,
+	CodeTimeout,
+	CodeInterrupt,
+	CodeTooManyRequests,
+	CodeUnauthorized,
+	CodeForbidden,
+	CodeConflict,
+	CodeUnprocessable,
+	CodeInvalid,
+	CodeBadRequest,
+	CodeNotFound,
+}
+
+// DefaultPrecedence returns a copy of the default precedence list, most
+// dominant first. Callers may reorder or extend it and pass it to
+// DominantCode.
+func DefaultPrecedence() []Code {
+	out := make([]Code, len(defaultPrecedence))
+	copy(out, defaultPrecedence)
+	return out
+}
+
+// DominantCode returns the most dominant branch code in err's graph under
+// precedence (most dominant first; nil selects DefaultPrecedence), or "" if
+// no branch carries a code.
+func DominantCode(err error, precedence []Code) Code {
+	if precedence == nil {
+		precedence = defaultPrecedence
+	}
+	d := dominance{
+		precedence: precedence,
+		bestRank:   -1,
+		seenErr:    make(map[error]struct{}, 8),
+		seenPtr:    make(map[uintptr]struct{}, 8),
+	}
+	d.branch(err, 0)
+	return d.best
+}
+
+// dominance accumulates the best-ranked branch code for DominantCode.
+type dominance struct {
+	precedence []Code
+	best       Code
+	bestRank   int
+	seenErr    map[error]struct{}
+	seenPtr    map[uintptr]struct{}
+}
+
+// branch follows err's cause chain to its outermost code and ranks it,
+// fanning out at joins. Cycle-safe via markSeen, like Walk.
+func (d *dominance) branch(err error, depth int) {
+	const maxDepth = 1 << 12 // generous cap against runaway graphs
+	for ; err != nil && depth < maxDepth; depth++ {
+		if d.bestRank == 0 || !markSeen(err, d.seenErr, d.seenPtr) {
+			return // nothing can beat the top entry, or already ranked
+		}
+		if m, ok := err.(multiUnwrapper); ok {
+			for _, c := range m.Unwrap() {
+				d.branch(c, depth+1)
+			}
+			return
+		}
+		if c, ok := err.(coder); ok && c.CodeVal() != "" {
+			d.rank(c.CodeVal())
+			return
+		}
+		if err == context.Canceled || err == context.DeadlineExceeded {
+			d.rank(CodeInterrupt)
+			return
+		}
+		s, ok := err.(singleUnwrapper)
+		if !ok {
+			return
+		}
+		err = s.Unwrap()
+	}
+}
+
+// rank keeps c if it outranks the best so far (ties keep the earlier one).
+func (d *dominance) rank(c Code) {
+	if r := precedenceRank(c, d.precedence); d.bestRank < 0 || r < d.bestRank {
+		d.best, d.bestRank = c, r
+	}
+}
+
+// precedenceRank is the index of the first entry c IS-A, or len(precedence).
+func precedenceRank(c Code, precedence []Code) int {
+	for i, p := range precedence {
+		if c.IsA(p) {
+			return i
+		}
+	}
+	return len(precedence)
+}
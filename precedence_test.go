@@ -0,0 +1,67 @@
+// precedence_test.go — verification of policy-based dominant codes.
+package xgxerror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestDominantCode_DefaultPrecedence(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{"nil", nil, ""},
+		{"foreign", errors.New("x"), ""},
+		{"single", NotFound("user", 1), CodeNotFound},
+		{"internal_over_invalid", Join(Invalid("email", "format"), Internal(errors.New("db"))), CodeInternal},
+		{"outage_over_validation", Join(Invalid("email", "format"), Unavailable("db")), CodeUnavailable},
+		{"defect_over_all", Join(Internal(nil), Interrupt("x"), Defect(errors.New("bug"))), CodeDefect},
+		{"internal_over_interrupt", Join(Interrupt("x"), Internal(nil)), CodeInternal},
+		{"interrupt_over_client", Join(Invalid("email", "format"), Interrupt("x")), CodeInterrupt},
+		{"ctx_sentinel", Join(NotFound("user", 1), fmt.Errorf("q: %w", context.DeadlineExceeded)), CodeInterrupt},
+		{"precise_code_kept", Join(NotFound("user", 1), Recode(nil, "unavailable.db")), "unavailable.db"},
+		{"unlisted_below_listed", Join(Recode(nil, "custom"), NotFound("user", 1)), CodeNotFound},
+		{"unlisted_first_wins", Join(Recode(nil, "custom.a"), Recode(nil, "custom.b")), "custom.a"},
+		{"nested", Join(Conflict("dup"), Join(BadRequest("x"), Wrap(Timeout(0), "call"))), CodeTimeout},
+		{"recode_beats_chain", Recode(Internal(Unavailable("db")), CodeNotFound), CodeNotFound},
+		{"outermost_per_branch", Join(Invalid("email", "format"), Recode(Unavailable("db"), CodeBadRequest)), CodeInvalid},
+		{"foreign_wrapper_looked_through", Join(NotFound("user", 1), fmt.Errorf("q: %w", Recode(Unavailable("db"), CodeConflict))), CodeConflict},
+		{"join_inside_chain", Join(NotFound("user", 1), fmt.Errorf("batch: %w", errors.Join(errors.New("x"), Timeout(0)))), CodeTimeout},
+	}
+	for _, tc := range cases {
+		if got := DominantCode(tc.err, nil); got != tc.want {
+			t.Fatalf("%s: DominantCode = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestDominantCode_CustomPrecedence(t *testing.T) {
+	t.Parallel()
+
+	err := Join(Invalid("email", "format"), Unavailable("db"), Recode(nil, "quota.exceeded"))
+	if got := DominantCode(err, []Code{"quota", CodeInvalid}); got != "quota.exceeded" {
+		t.Fatalf("custom precedence: %q", got)
+	}
+	if got := DominantCode(err, []Code{}); got != CodeInvalid {
+		t.Fatalf("empty precedence ranks everything equal (first wins): %q", got)
+	}
+}
+
+func TestDefaultPrecedence_IsACopy(t *testing.T) {
+	t.Parallel()
+
+	p := DefaultPrecedence()
+	if p[0] != CodeDefect || p[len(p)-1] != CodeNotFound {
+		t.Fatalf("unexpected default order: %v", p)
+	}
+	p[0] = "mutated"
+	if DefaultPrecedence()[0] != CodeDefect {
+		t.Fatalf("DefaultPrecedence must return a copy")
+	}
+}
@@ -8,11 +8,19 @@
 // Notes:
 //   - Interrupts are detected via errors.Is against context.Canceled /
 //     context.DeadlineExceeded (canonical stdlib sentinels).
-//   - HasCode / IsRetryable scan the entire unwrap graph (all branches).
-//   - CodeOf returns the first discovered Code via errors.As (first match).
+//   - HasCode / IsRetryable scan the entire unwrap graph (all branches) and
+//     honor the dotted code hierarchy (see Code.IsA).
+//   - CodeOf returns the first Code discovered in DFS order. Like HasCode it
+//     reads any node exposing CodeVal: native errors, CodeErr sentinels and
+//     foreign types alike.
+//   - DominantCode (precedence.go) ranks the code of every join branch instead.
+//   - Joins report an aggregate code (see (*multi).CodeVal), which would stop
+//     errors.As at the join; code predicates therefore walk the graph and skip
+//     join nodes, judging only the codes their branches actually carry.
 //
 // Out of scope (by design):
 //   - HTTP/status mapping, retry backoff policy, logging.
+//   - Retryability itself is metadata: see Registry in registry.go.
 package xgxerror
 
 import (
@@ -25,8 +33,8 @@ type coder interface{ CodeVal() Code }
 
 // IsDefect reports whether err is (or wraps) a programming defect.
 //
-// It matches either the concrete internal defect type or any value that
-// implements CodeVal()==CodeDefect. Traversal follows stdlib rules and
+// It matches either the concrete internal defect type or any value whose
+// CodeVal() IS-A CodeDefect. Traversal follows stdlib rules and
 // includes joined graphs.
 func IsDefect(err error) bool {
 	if err == nil {
@@ -38,14 +46,13 @@ func IsDefect(err error) bool {
 		return true
 	}
 	// Or anything reporting CodeDefect.
-	var c coder
-	return errors.As(err, &c) && c.CodeVal() == CodeDefect
+	return HasCode(err, CodeDefect)
 }
 
 // IsInterrupt reports whether err denotes cooperative cancellation or a deadline.
 //
 // Returns true if any branch unwraps to context.Canceled or
-// context.DeadlineExceeded, or if a node reports CodeInterrupt.
+// context.DeadlineExceeded, or if a node reports a code that IS-A CodeInterrupt.
 func IsInterrupt(err error) bool {
 	if err == nil {
 		return false
@@ -60,38 +67,48 @@ func IsInterrupt(err error) bool {
 		return true
 	}
 	// Or anything reporting CodeInterrupt.
-	var c coder
-	return errors.As(err, &c) && c.CodeVal() == CodeInterrupt
+	return HasCode(err, CodeInterrupt)
 }
 
-// HasCode reports whether any node in the graph carries the given code.
+// HasCode reports whether any node in the graph carries the given code or a
+// descendant of it (e.g., "not_found.user" matches CodeNotFound).
 func HasCode(err error, want Code) bool {
-    found := false
-    Walk(err, func(e error) bool {
-        if xe, ok := e.(Error); ok && xe.CodeVal() == want {
-            found = true
-            return false // stop early
-        }
-        return true
-    })
-    return found
+	found := false
+	Walk(err, func(e error) bool {
+		if _, ok := e.(*multi); ok {
+			return true // aggregate; its branches are visited on their own
+		}
+		if c, ok := e.(coder); ok && c.CodeVal().IsA(want) {
+			found = true
+			return false // stop early
+		}
+		return true
+	})
+	return found
 }
 
-// IsRetryable is a tiny, policy-free heuristic based on commonly transient codes.
-// Returns true if ANY branch reports one of: unavailable, timeout, too_many_requests.
-// Backoff/budgets belong in higher layers.
+// IsRetryable reports whether ANY branch carries a code that resolves to a
+// retryable entry in the default registry (unavailable, timeout and
+// too_many_requests out of the box, including descendants such as
+// "unavailable.db.primary"). Backoff/budgets belong in higher layers.
 func IsRetryable(err error) bool {
-	if err == nil {
+	return IsRetryableIn(err, defaultRegistry)
+}
+
+// IsRetryableIn is like IsRetryable but consults r instead of the default
+// registry. A nil registry reports false.
+func IsRetryableIn(err error, r *Registry) bool {
+	if err == nil || r == nil {
 		return false
 	}
 	retryable := false
 	Walk(err, func(e error) bool {
-		if c, ok := e.(coder); ok {
-			switch c.CodeVal() {
-			case CodeUnavailable, CodeTimeout, CodeTooManyRequests:
-				retryable = true
-				return false // early exit
-			}
+		if _, ok := e.(*multi); ok {
+			return true // aggregate; its branches are visited on their own
+		}
+		if c, ok := e.(coder); ok && r.Retryable(c.CodeVal()) {
+			retryable = true
+			return false // early exit
 		}
 		return true
 	})
@@ -99,14 +116,22 @@ func IsRetryable(err error) bool {
 }
 
 // CodeOf returns the first code encountered in DFS order (or "").
+// The code is returned as-is; use Code.IsA to compare against ancestors.
+// For joined failures, DominantCode picks the most severe code instead.
 func CodeOf(err error) Code {
-    var out Code
-    Walk(err, func(e error) bool {
-        if xe, ok := e.(Error); ok {
-            out = xe.CodeVal()
-            return false
-        }
-        return true
-    })
-    return out
-}
\ No newline at end of file
+	var out Code
+	Walk(err, func(e error) bool {
+		if _, ok := e.(*multi); ok {
+			return true // first child code, not the aggregate
+		}
+		if f, ok := e.(*failureErr); ok && f.isTag() {
+			return true // codeless tag (see Adopt); look at its cause
+		}
+		if c, ok := e.(coder); ok {
+			out = c.CodeVal()
+			return false
+		}
+		return true
+	})
+	return out
+}
@@ -4,6 +4,7 @@ package xgxerror
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 )
@@ -210,3 +211,66 @@ type stdlibWrap struct {
 
 func (w *stdlibWrap) Error() string { return w.msg + ": " + w.cause.Error() }
 func (w *stdlibWrap) Unwrap() error { return w.cause }
+
+func TestHasCode_MatchesHierarchicalDescendants(t *testing.T) {
+	t.Parallel()
+
+	err := Join(BadRequest("x"), Recode(nil, "not_found.user"))
+	if !HasCode(err, CodeNotFound) {
+		t.Fatalf("HasCode(not_found.user, not_found) = false, want true")
+	}
+	if !HasCode(err, "not_found.user") {
+		t.Fatalf("HasCode exact match lost")
+	}
+	if HasCode(err, "not_found.user.email") {
+		t.Fatalf("HasCode must not match a more specific code")
+	}
+	if CodeOf(Recode(nil, "not_found.user")) != "not_found.user" {
+		t.Fatalf("CodeOf must return the precise code")
+	}
+}
+
+func TestIsRetryable_MatchesHierarchicalDescendants(t *testing.T) {
+	t.Parallel()
+
+	if !IsRetryable(Recode(errors.New("conn reset"), "unavailable.db.primary")) {
+		t.Fatalf("IsRetryable(unavailable.db.primary) = false, want true")
+	}
+	if IsRetryable(Recode(nil, "not_found.user")) {
+		t.Fatalf("IsRetryable(not_found.user) = true, want false")
+	}
+}
+
+func TestIsDefectAndIsInterrupt_MatchDescendantCodes(t *testing.T) {
+	t.Parallel()
+
+	if !IsDefect(Recode(nil, "defect.invariant")) {
+		t.Fatalf("IsDefect(defect.invariant) = false")
+	}
+	if !IsInterrupt(Recode(nil, "interrupt.shutdown")) {
+		t.Fatalf("IsInterrupt(interrupt.shutdown) = false")
+	}
+}
+
+// foreignCoder is a non-xgx error that reports a code.
+type foreignCoder Code
+
+func (c foreignCoder) Error() string { return string(c) }
+func (c foreignCoder) CodeVal() Code { return Code(c) }
+
+func TestIsDefectAndIsInterrupt_LookPastJoinAggregate(t *testing.T) {
+	t.Parallel()
+
+	// The join's aggregate CodeVal is "defect"; errors.As would stop there
+	// and miss the interrupt branch.
+	j := Join(Defect(errors.New("bug")), foreignCoder("interrupt.shutdown"))
+	if !IsInterrupt(j) || !IsDefect(j) {
+		t.Fatalf("IsInterrupt=%v IsDefect=%v, want both", IsInterrupt(j), IsDefect(j))
+	}
+	if IsDefect(Join(NotFound("user", 1), foreignCoder("conflict"))) {
+		t.Fatalf("IsDefect on a join without defects")
+	}
+	if !IsDefect(Join(NotFound("user", 1), fmt.Errorf("x: %w", foreignCoder("defect.invariant")))) {
+		t.Fatalf("IsDefect should find a wrapped foreign defect code in a branch")
+	}
+}
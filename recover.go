@@ -0,0 +1,97 @@
+// recover.go — turning recovered panics into Defect errors.
+//
+// Behavior:
+//   - Recover(&err) is deferred directly; on panic it stores a Defect in *err
+//     (joined with any error already there) and lets the function return.
+//   - Safe(fn) runs fn under Recover; SafeGo(fn) does so on a new goroutine
+//     and delivers the result on a channel.
+//   - The stack is the PANIC SITE: frames of the recover machinery and the
+//     runtime's panic frames (runtime.gopanic, runtime.sigpanic, ...) are
+//     trimmed, so the first frame is the code that panicked.
+//   - The panic value is kept as FieldPanicValue; if it is an error it is also
+//     the cause (errors.Is/As see through). runtime.Error panics (nil
+//     dereference, index out of range, ...) carry FieldRuntimeError=true.
+//
+// Example:
+//
+//	func handle() (err error) {
+//	    defer xgxerror.Recover(&err)
+//	    ...
+//	}
+package xgxerror
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// FieldPanicValue holds the value passed to panic.
+var FieldPanicValue = FieldOf[any]("panic_value")
+
+// FieldRuntimeError is true when the panic value is a runtime.Error.
+var FieldRuntimeError = FieldOf[bool]("runtime_error")
+
+// Recover converts a panic into a Defect stored in *errp. It must be called
+// directly by defer. A non-nil *errp is kept, joined after the defect. A nil
+// errp re-panics with the original value.
+func Recover(errp *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if errp == nil {
+		panic(r)
+	}
+	*errp = Join(panicDefect(r), *errp)
+}
+
+// Safe calls fn and converts a panic into a Defect.
+func Safe(fn func() error) (err error) {
+	defer Recover(&err)
+	return fn()
+}
+
+// SafeGo runs fn on a new goroutine under Safe. The returned channel
+// receives fn's result (nil on success) and is then closed.
+func SafeGo(fn func() error) <-chan error {
+	ch := make(chan error, 1)
+	go func() {
+		defer close(ch)
+		ch <- Safe(fn)
+	}()
+	return ch
+}
+
+// panicDefect builds the defect for a recovered value. It must run inside the
+// deferred call so the panicking frames are still on the stack.
+func panicDefect(r any) Error {
+	d := &defectErr{
+		msg: "panic: " + fmt.Sprint(r),
+		stk: panicSite(captureStackDefault(0)),
+	}
+	if err, ok := r.(error); ok {
+		d.cause = err
+	}
+	_, isRuntime := r.(runtime.Error)
+	d.ctx = ctxFromKV(FieldPanicValue.Key(), r, FieldRuntimeError.Key(), isRuntime)
+	return d
+}
+
+// panicSite drops frames up to runtime.gopanic and the runtime frames that
+// raised it. Stacks captured outside a panic are returned unchanged. Panics
+// are rare, so resolving frames eagerly here is acceptable.
+func panicSite(stk Stack) Stack {
+	frames := stk.Frames()
+	for i, fr := range frames {
+		if fr.Function != "runtime.gopanic" {
+			continue
+		}
+		j := i + 1
+		for j < len(frames) && strings.HasPrefix(frames[j].Function, "runtime.") {
+			j++
+		}
+		return StackFromFrames(frames[j:])
+	}
+	return stk
+}
@@ -0,0 +1,125 @@
+// recover_test.go — verification of panic recovery into Defect errors.
+package xgxerror
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+//go:noinline
+func panicsWithIndex() error {
+	var s []int
+	_ = s[3]
+	return nil
+}
+
+//go:noinline
+func panicsWithValue() error {
+	panic("boom")
+}
+
+func TestSafe_PanicBecomesDefectWithPanicSiteStack(t *testing.T) {
+	t.Parallel()
+
+	err := Safe(panicsWithValue)
+	if !IsDefect(err) {
+		t.Fatalf("want defect, got %v", err)
+	}
+	if err.Error() != "defect: panic: boom" {
+		t.Fatalf("message: %q", err.Error())
+	}
+	xe := err.(Error)
+	if v, _ := FieldPanicValue.Get(xe); v != "boom" {
+		t.Fatalf("panic_value: %v", v)
+	}
+	if rt, _ := FieldRuntimeError.Get(xe); rt {
+		t.Fatalf("string panic must not be marked as runtime error")
+	}
+	stk := Inspect(err).Stack.Frames()
+	if len(stk) == 0 || !strings.HasSuffix(stk[0].Function, ".panicsWithValue") {
+		t.Fatalf("first frame should be the panic site, got %+v", stk)
+	}
+}
+
+func TestSafe_RuntimeErrorIsMarkedAndBecomesCause(t *testing.T) {
+	t.Parallel()
+
+	err := Safe(panicsWithIndex)
+	xe := err.(Error)
+	if rt, ok := FieldRuntimeError.Get(xe); !ok || !rt {
+		t.Fatalf("runtime_error should be true")
+	}
+	var re interface{ RuntimeError() }
+	if !errors.As(err, &re) {
+		t.Fatalf("runtime.Error should be the cause: %v", err)
+	}
+	if stk := Inspect(err).Stack.Frames(); len(stk) == 0 || !strings.HasSuffix(stk[0].Function, ".panicsWithIndex") {
+		t.Fatalf("runtime frames not trimmed: %+v", stk)
+	}
+}
+
+func TestSafe_ErrorPanicValueIsCause(t *testing.T) {
+	t.Parallel()
+
+	sentinel := errors.New("bad state")
+	err := Safe(func() error { panic(fmt.Errorf("wrap: %w", sentinel)) })
+	if !errors.Is(err, sentinel) || !IsDefect(err) {
+		t.Fatalf("error panic value should be the cause: %v", err)
+	}
+	if Safe(func() error { return nil }) != nil {
+		t.Fatalf("no panic should yield nil")
+	}
+	if got := Safe(func() error { return BadRequest("x") }); CodeOf(got) != CodeBadRequest {
+		t.Fatalf("returned error should pass through: %v", got)
+	}
+}
+
+func TestRecover_JoinsExistingErrorAndRepanicsOnNilPointer(t *testing.T) {
+	t.Parallel()
+
+	f := func() (err error) {
+		defer Recover(&err)
+		err = NotFound("user", 1) // set before a late panic
+		panic("late")
+	}
+	err := f()
+	if !IsDefect(err) || !HasCode(err, CodeNotFound) {
+		t.Fatalf("existing error should be joined with the defect: %v", err)
+	}
+
+	defer func() {
+		if r := recover(); r != "again" {
+			t.Fatalf("nil errp should re-panic with the original value, got %v", r)
+		}
+	}()
+	func() {
+		defer Recover(nil)
+		panic("again")
+	}()
+}
+
+func TestSafeGo_DeliversResultAndCloses(t *testing.T) {
+	t.Parallel()
+
+	ch := SafeGo(panicsWithValue)
+	if err := <-ch; !IsDefect(err) {
+		t.Fatalf("want defect from goroutine, got %v", err)
+	}
+	if _, open := <-ch; open {
+		t.Fatalf("channel should be closed after the result")
+	}
+	if err := <-SafeGo(func() error { return nil }); err != nil {
+		t.Fatalf("want nil, got %v", err)
+	}
+}
+
+func TestPanicSite_UnchangedOutsidePanics(t *testing.T) {
+	t.Parallel()
+
+	stk := StackFromFrames([]Frame{{Function: "a"}, {Function: "b"}})
+	if got := panicSite(stk); got.Len() != 2 {
+		t.Fatalf("stack without gopanic should be unchanged: %+v", got.Frames())
+	}
+}
@@ -0,0 +1,148 @@
+// redact.go — sensitive context values and key-based redaction policy.
+//
+// Two ways to keep secrets out of rendered errors:
+//   - Per value: wrap it with Secret(v) (or use a TypedField declared with
+//     FieldSensitive). The wrapper renders as RedactedText everywhere.
+//   - Per key: the package-level policy (SetRedactedKeys) redacts values whose
+//     key matches, even when the author forgot to wrap them.
+//
+// Where redaction applies:
+//   - %+v (formatVerbose), Context(), and exporters that render via
+//     Field.Redacted (jsonx, slog, HTTP adapters).
+//
+// Where it does NOT apply (privileged, programmatic access):
+//   - TypedField.Get/MustGet and Reveal return the raw value.
+//   - Inspect returns fields as stored; exporters must call Field.Redacted.
+package xgxerror
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+)
+
+// RedactedText is the placeholder rendered in place of sensitive values.
+const RedactedText = "[REDACTED]"
+
+// SecretValue wraps a context value that must never be rendered.
+// Its zero value is a redacted nil.
+type SecretValue struct {
+	v any
+}
+
+// Secret marks v as sensitive. Attach it like any other value:
+//
+//	err = err.With("email", xgxerror.Secret(u.Email))
+func Secret(v any) SecretValue { return SecretValue{v: v} }
+
+// Reveal returns the wrapped raw value. Use only in privileged code paths.
+func (s SecretValue) Reveal() any { return s.v }
+
+// String implements fmt.Stringer and always returns RedactedText.
+func (s SecretValue) String() string { return RedactedText }
+
+// GoString implements fmt.GoStringer so %#v does not leak the value.
+func (s SecretValue) GoString() string { return RedactedText }
+
+// Format implements fmt.Formatter; every verb renders RedactedText.
+func (s SecretValue) Format(f fmt.State, _ rune) { _, _ = io.WriteString(f, RedactedText) }
+
+// MarshalJSON renders RedactedText as a JSON string.
+func (s SecretValue) MarshalJSON() ([]byte, error) { return []byte(`"` + RedactedText + `"`), nil }
+
+// defaultRedactedKeys are redacted unless the policy is replaced.
+var defaultRedactedKeys = []string{
+	"password", "passwd", "secret", "token", "access_token", "refresh_token",
+	"api_key", "authorization", "cookie", "set_cookie",
+}
+
+// redactedKeys holds the active key policy (lowercase set); never nil.
+var redactedKeys atomic.Pointer[map[string]struct{}]
+
+func init() { SetRedactedKeys(defaultRedactedKeys...) }
+
+// SetRedactedKeys replaces the package-level redaction policy. Matching is
+// case-insensitive on the whole key. Calling it with no keys disables
+// key-based redaction (Secret values stay redacted). Safe for concurrent use.
+func SetRedactedKeys(keys ...string) {
+	m := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		if k != "" {
+			m[strings.ToLower(k)] = struct{}{}
+		}
+	}
+	redactedKeys.Store(&m)
+}
+
+// RedactedKeys returns the active key policy in no particular order.
+func RedactedKeys() []string {
+	m := *redactedKeys.Load()
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
+
+// IsRedactedKey reports whether the policy redacts values stored under key.
+func IsRedactedKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	m := *redactedKeys.Load()
+	if _, ok := m[key]; ok {
+		return true
+	}
+	_, ok := m[strings.ToLower(key)]
+	return ok
+}
+
+// Sensitive reports whether f must be redacted when rendered, either because
+// its value is a SecretValue or because its key matches the redaction policy.
+func (f Field) Sensitive() bool {
+	if _, ok := f.Val.(SecretValue); ok {
+		return true
+	}
+	return IsRedactedKey(f.Key)
+}
+
+// Redacted returns f with its value replaced by RedactedText if f is
+// sensitive; otherwise it returns f unchanged. Renderers and exporters call
+// this before emitting a field.
+func (f Field) Redacted() Field {
+	if f.Sensitive() {
+		f.Val = RedactedText
+	}
+	return f
+}
+
+// Reveal returns the newest raw value stored under key on e, unwrapping
+// SecretValue. It is the privileged counterpart of Context(), which redacts.
+// Foreign Error implementations only expose what their Context() returns.
+func Reveal(e Error, key string) (any, bool) {
+	if e == nil {
+		return nil, false
+	}
+	var (
+		v  any
+		ok bool
+	)
+	if lk, isNative := any(e).(fieldLookup); isNative {
+		v, ok = lk.lookupFieldLast(key)
+	} else {
+		v, ok = e.Context()[key]
+	}
+	if !ok {
+		return nil, false
+	}
+	return unwrapSecret(v), true
+}
+
+// unwrapSecret returns the raw value behind a SecretValue, or v itself.
+func unwrapSecret(v any) any {
+	if s, ok := v.(SecretValue); ok {
+		return s.v
+	}
+	return v
+}
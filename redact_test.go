@@ -0,0 +1,148 @@
+// redact_test.go — verification of sensitive values and key-based redaction.
+package xgxerror
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSecret_RendersRedactedInEveryVerb(t *testing.T) {
+	t.Parallel()
+
+	s := Secret("hunter2")
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x", "%d"} {
+		if out := fmt.Sprintf(verb, s); strings.Contains(out, "hunter2") || !strings.Contains(out, RedactedText) {
+			t.Fatalf("Sprintf(%s, Secret) = %q; want %s", verb, out, RedactedText)
+		}
+	}
+	b, err := json.Marshal(s)
+	if err != nil || string(b) != `"`+RedactedText+`"` {
+		t.Fatalf("MarshalJSON = %s, %v", b, err)
+	}
+	if s.Reveal() != "hunter2" {
+		t.Fatalf("Reveal lost raw value")
+	}
+}
+
+func TestRedaction_VerboseFormatAndContext(t *testing.T) {
+	t.Parallel()
+
+	e := BadRequest("login failed").
+		Ctx("", "user", "alice", "email", Secret("a@example.com"), "password", "hunter2", "Authorization", "Bearer abc")
+
+	out := fmt.Sprintf("%+v", e)
+	notContains(t, out, "a@example.com", "hunter2", "Bearer abc")
+	containsAll(t, out, "user=alice", "email="+RedactedText, "password="+RedactedText, "Authorization="+RedactedText)
+
+	ctx := e.Context()
+	if ctx["user"] != "alice" {
+		t.Fatalf("non-sensitive value altered: %v", ctx["user"])
+	}
+	for _, k := range []string{"email", "password", "Authorization"} {
+		if ctx[k] != RedactedText {
+			t.Fatalf("Context()[%q] = %v, want %s", k, ctx[k], RedactedText)
+		}
+	}
+}
+
+func TestRedaction_NestedCauseIsRedacted(t *testing.T) {
+	t.Parallel()
+
+	inner := Unauthorized("bad token").With("token", "tok_live_123")
+	outer := Internal(inner).With("api_key", Secret(42))
+
+	out := fmt.Sprintf("%+v", outer)
+	notContains(t, out, "tok_live_123", "api_key=42")
+}
+
+func TestReveal_ReturnsRawValues(t *testing.T) {
+	t.Parallel()
+
+	e := BadRequest("x").With("email", Secret("a@example.com")).With("password", "hunter2")
+	if v, ok := Reveal(e, "email"); !ok || v != "a@example.com" {
+		t.Fatalf("Reveal(email) = %v, %v", v, ok)
+	}
+	if v, ok := Reveal(e, "password"); !ok || v != "hunter2" {
+		t.Fatalf("Reveal(password) = %v, %v", v, ok)
+	}
+	if _, ok := Reveal(e, "missing"); ok {
+		t.Fatalf("Reveal(missing) should report false")
+	}
+	if _, ok := Reveal(nil, "email"); ok {
+		t.Fatalf("Reveal(nil) should report false")
+	}
+}
+
+func TestTypedField_SensitiveOption(t *testing.T) {
+	t.Parallel()
+
+	fEmail := FieldOf[string]("email", FieldSensitive)
+	if !fEmail.Sensitive() || FieldOf[string]("email").Sensitive() {
+		t.Fatalf("Sensitive() does not reflect the option")
+	}
+
+	e := fEmail.Set(NotFound("user", 1), "a@example.com")
+	if v, ok := fEmail.Get(e); !ok || v != "a@example.com" {
+		t.Fatalf("Get on sensitive field = %q, %v; want raw value", v, ok)
+	}
+	if v := fEmail.MustGet(e); v != "a@example.com" {
+		t.Fatalf("MustGet on sensitive field = %q", v)
+	}
+	// A plain field reading a Secret-wrapped value also unwraps.
+	if v, ok := FieldOf[string]("email").Get(e); !ok || v != "a@example.com" {
+		t.Fatalf("plain Get over Secret = %q, %v", v, ok)
+	}
+	if out := fmt.Sprintf("%+v", e); strings.Contains(out, "a@example.com") {
+		t.Fatalf("sensitive typed field leaked in %%+v:\n%s", out)
+	}
+}
+
+func TestField_SensitiveAndRedacted(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		f    Field
+		want bool
+	}{
+		{Field{Key: "password", Val: "x"}, true},
+		{Field{Key: "PASSWORD", Val: "x"}, true},
+		{Field{Key: "user", Val: Secret("x")}, true},
+		{Field{Key: "user", Val: "x"}, false},
+		{Field{Key: "password_hint", Val: "x"}, false}, // whole-key match only
+		{Field{Key: "", Val: "x"}, false},
+	}
+	for _, tc := range cases {
+		if got := tc.f.Sensitive(); got != tc.want {
+			t.Fatalf("Field{%q}.Sensitive() = %v, want %v", tc.f.Key, got, tc.want)
+		}
+		r := tc.f.Redacted()
+		if tc.want && r.Val != RedactedText {
+			t.Fatalf("Field{%q}.Redacted().Val = %v", tc.f.Key, r.Val)
+		}
+		if !tc.want && r != tc.f {
+			t.Fatalf("Field{%q}.Redacted() altered non-sensitive field", tc.f.Key)
+		}
+	}
+}
+
+func TestSetRedactedKeys_ReplacesPolicy(t *testing.T) {
+	// Not parallel: mutates the package-level policy.
+	prev := RedactedKeys()
+	t.Cleanup(func() { SetRedactedKeys(prev...) })
+
+	SetRedactedKeys("SSN")
+	if !IsRedactedKey("ssn") || IsRedactedKey("password") {
+		t.Fatalf("policy not replaced: ssn=%v password=%v", IsRedactedKey("ssn"), IsRedactedKey("password"))
+	}
+	e := BadRequest("x").Ctx("", "ssn", "123-45-6789", "password", "visible-now", "note", Secret("still hidden"))
+	out := fmt.Sprintf("%+v", e)
+	notContains(t, out, "123-45-6789", "still hidden")
+	containsAll(t, out, "password=visible-now")
+
+	SetRedactedKeys()
+	if len(RedactedKeys()) != 0 || IsRedactedKey("ssn") {
+		t.Fatalf("empty policy should disable key-based redaction")
+	}
+}
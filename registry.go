@@ -0,0 +1,190 @@
+// registry.go — opt-in code metadata registry for xgx-error core.
+//
+// Intent:
+//   - Let teams describe their codes once (description, default message,
+//     severity, retryability, transport mappings) and let predicates and
+//     adapters consult that description instead of hard-coded switches.
+//   - Keep Code itself a plain string: registering is optional, and unknown
+//     codes keep working everywhere (they simply have no metadata).
+//
+// Semantics:
+//   - A Registry is safe for concurrent use.
+//   - Register rejects empty and duplicate codes; entries are immutable once
+//     registered.
+//   - Resolve honors the dotted code hierarchy: an unregistered
+//     "unavailable.db.primary" inherits the nearest registered ancestor.
+//   - List returns entries in registration order (built-ins first for the
+//     default registry) so generated docs are stable.
+//   - The default registry is pre-filled with the 13 built-in codes; projects
+//     add their own with RegisterCode or build an isolated NewRegistry.
+package xgxerror
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Severity ranks how operationally serious a code is. Higher is more severe.
+type Severity uint8
+
+const (
+	SeverityUnspecified Severity = iota
+	SeverityInfo                 // expected outcome; no action needed
+	SeverityWarning              // caller or client mistake; worth tracking
+	SeverityError                // service failed to fulfil the request
+	SeverityCritical             // invariant broken; page someone
+)
+
+// String returns the lowercase name of the severity (e.g., "warning").
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "unspecified"
+	}
+}
+
+// CodeInfo is the metadata registered for a single Code.
+//
+// HTTPStatus and GRPCCode are advisory mappings for transport adapters; a
+// zero value means "no mapping". GRPCCode uses the canonical numbering of
+// google.golang.org/grpc/codes (e.g., 5 = NotFound) without depending on it.
+type CodeInfo struct {
+	Code           Code
+	Description    string
+	DefaultMessage string
+	Severity       Severity
+	Retryable      bool
+	HTTPStatus     int
+	GRPCCode       uint32
+}
+
+// Registry maps codes to their metadata.
+type Registry struct {
+	mu    sync.RWMutex
+	byKey map[Code]int // index into order
+	order []CodeInfo
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{byKey: make(map[Code]int)}
+}
+
+// Register adds info to r. It fails if info.Code is empty or already registered.
+func (r *Registry) Register(info CodeInfo) error {
+	if info.Code == "" {
+		return fmt.Errorf("xgxerror.Registry: cannot register empty code")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, dup := r.byKey[info.Code]; dup {
+		return fmt.Errorf("xgxerror.Registry: code %q already registered", info.Code)
+	}
+	r.byKey[info.Code] = len(r.order)
+	r.order = append(r.order, info)
+	return nil
+}
+
+// MustRegister is like Register but panics on error. Intended for init().
+func (r *Registry) MustRegister(info CodeInfo) {
+	if err := r.Register(info); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the metadata registered for exactly c.
+func (r *Registry) Lookup(c Code) (CodeInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i, ok := r.byKey[c]
+	if !ok {
+		return CodeInfo{}, false
+	}
+	return r.order[i], true
+}
+
+// Resolve returns the metadata for c or, if c is not registered, for its
+// nearest registered ancestor (see Code.Parent). The returned CodeInfo keeps
+// the ancestor's Code so callers can tell which entry matched.
+func (r *Registry) Resolve(c Code) (CodeInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for ; c != ""; c = c.Parent() {
+		if i, ok := r.byKey[c]; ok {
+			return r.order[i], true
+		}
+	}
+	return CodeInfo{}, false
+}
+
+// List returns a copy of all entries in registration order.
+func (r *Registry) List() []CodeInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]CodeInfo, len(r.order))
+	copy(out, r.order)
+	return out
+}
+
+// Retryable reports whether c resolves to an entry registered as retryable.
+// Codes with no registered ancestor are not retryable.
+func (r *Registry) Retryable(c Code) bool {
+	info, ok := r.Resolve(c)
+	return ok && info.Retryable
+}
+
+// builtinCodeInfos describes the built-in codes, in BuiltinCodes() order.
+var builtinCodeInfos = []CodeInfo{
+	{Code: CodeBadRequest, Description: "The request is malformed.", DefaultMessage: "bad request",
+		Severity: SeverityWarning, HTTPStatus: 400, GRPCCode: 3},
+	{Code: CodeUnauthorized, Description: "The caller is not authenticated.", DefaultMessage: "unauthorized",
+		Severity: SeverityWarning, HTTPStatus: 401, GRPCCode: 16},
+	{Code: CodeForbidden, Description: "The caller lacks permission for the resource.", DefaultMessage: "forbidden",
+		Severity: SeverityWarning, HTTPStatus: 403, GRPCCode: 7},
+	{Code: CodeNotFound, Description: "The requested entity does not exist.", DefaultMessage: "not found",
+		Severity: SeverityInfo, HTTPStatus: 404, GRPCCode: 5},
+	{Code: CodeConflict, Description: "The request conflicts with the current state.", DefaultMessage: "conflict",
+		Severity: SeverityWarning, HTTPStatus: 409, GRPCCode: 6},
+	{Code: CodeInvalid, Description: "An input value failed validation.", DefaultMessage: "invalid input",
+		Severity: SeverityInfo, HTTPStatus: 400, GRPCCode: 3},
+	{Code: CodeUnprocessable, Description: "The request is well-formed but semantically unacceptable.", DefaultMessage: "unprocessable",
+		Severity: SeverityInfo, HTTPStatus: 422, GRPCCode: 9},
+	{Code: CodeTooManyRequests, Description: "A rate limit or quota was exceeded.", DefaultMessage: "too many requests",
+		Severity: SeverityWarning, Retryable: true, HTTPStatus: 429, GRPCCode: 8},
+	{Code: CodeTimeout, Description: "An operation took longer than allowed.", DefaultMessage: "timeout",
+		Severity: SeverityError, Retryable: true, HTTPStatus: 504, GRPCCode: 4},
+	{Code: CodeUnavailable, Description: "A dependency is temporarily unavailable.", DefaultMessage: "unavailable",
+		Severity: SeverityError, Retryable: true, HTTPStatus: 503, GRPCCode: 14},
+	{Code: CodeInternal, Description: "An unexpected failure inside the service.", DefaultMessage: defaultInternalMsg,
+		Severity: SeverityError, HTTPStatus: 500, GRPCCode: 13},
+	{Code: CodeDefect, Description: "A programming error or broken invariant.", DefaultMessage: "defect",
+		Severity: SeverityCritical, HTTPStatus: 500, GRPCCode: 13},
+	{Code: CodeInterrupt, Description: "The operation was canceled or its deadline expired.", DefaultMessage: "interrupt",
+		Severity: SeverityInfo, HTTPStatus: 499, GRPCCode: 1},
+}
+
+// defaultRegistry is pre-filled with the built-in codes.
+var defaultRegistry = newBuiltinRegistry()
+
+func newBuiltinRegistry() *Registry {
+	r := NewRegistry()
+	for _, info := range builtinCodeInfos {
+		r.MustRegister(info)
+	}
+	return r
+}
+
+// DefaultRegistry returns the package-wide registry consulted by IsRetryable
+// and adapters that are not given an explicit registry.
+func DefaultRegistry() *Registry { return defaultRegistry }
+
+// RegisterCode adds info to the default registry.
+func RegisterCode(info CodeInfo) error { return defaultRegistry.Register(info) }
@@ -0,0 +1,200 @@
+// registry_test.go — verification of the code metadata registry.
+package xgxerror
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestDefaultRegistry_CoversBuiltinsInOrder(t *testing.T) {
+	t.Parallel()
+
+	list := DefaultRegistry().List()
+	builtins := BuiltinCodes()
+	if len(list) < len(builtins) {
+		t.Fatalf("default registry has %d entries, want >= %d", len(list), len(builtins))
+	}
+	for i, c := range builtins {
+		if list[i].Code != c {
+			t.Fatalf("entry %d: want %q got %q", i, c, list[i].Code)
+		}
+		if list[i].Description == "" || list[i].DefaultMessage == "" || list[i].HTTPStatus == 0 {
+			t.Fatalf("entry %q lacks metadata: %+v", c, list[i])
+		}
+	}
+}
+
+func TestDefaultRegistry_RetryableSetMatchesHistoricalHeuristic(t *testing.T) {
+	t.Parallel()
+
+	want := map[Code]bool{CodeUnavailable: true, CodeTimeout: true, CodeTooManyRequests: true}
+	for _, c := range BuiltinCodes() {
+		if got := DefaultRegistry().Retryable(c); got != want[c] {
+			t.Fatalf("Retryable(%q) = %v, want %v", c, got, want[c])
+		}
+	}
+}
+
+func TestRegistry_RegisterLookupAndDuplicates(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	info := CodeInfo{Code: "payment_declined", Description: "card declined", Severity: SeverityWarning, HTTPStatus: 402}
+	if err := r.Register(info); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	got, ok := r.Lookup("payment_declined")
+	if !ok || got != info {
+		t.Fatalf("Lookup = %+v, %v; want %+v", got, ok, info)
+	}
+	if _, ok := r.Lookup(CodeNotFound); ok {
+		t.Fatalf("fresh registry should not contain built-ins")
+	}
+
+	err := r.Register(CodeInfo{Code: "payment_declined"})
+	if err == nil || !strings.Contains(err.Error(), "already registered") {
+		t.Fatalf("duplicate Register: want error, got %v", err)
+	}
+	if err := r.Register(CodeInfo{}); err == nil {
+		t.Fatalf("empty code Register: want error")
+	}
+	if got, _ := r.Lookup("payment_declined"); got != info {
+		t.Fatalf("duplicate registration overwrote entry: %+v", got)
+	}
+}
+
+func TestRegistry_ListIsOrderedCopy(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	r.MustRegister(CodeInfo{Code: "b"})
+	r.MustRegister(CodeInfo{Code: "a"})
+	list := r.List()
+	if len(list) != 2 || list[0].Code != "b" || list[1].Code != "a" {
+		t.Fatalf("List order: %+v", list)
+	}
+	list[0].Code = "mutated"
+	if r.List()[0].Code != "b" {
+		t.Fatalf("List leaked internal slice")
+	}
+}
+
+func TestRegistry_MustRegisterPanicsOnDuplicate(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	r.MustRegister(CodeInfo{Code: "x"})
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic on duplicate MustRegister")
+		}
+	}()
+	r.MustRegister(CodeInfo{Code: "x"})
+}
+
+func TestRegistry_ConcurrentRegisterAndLookup(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c := Code("c" + strings.Repeat("x", i))
+			_ = r.Register(CodeInfo{Code: c, Retryable: i%2 == 0})
+			_ = r.Retryable(c)
+			_ = r.List()
+		}(i)
+	}
+	wg.Wait()
+	if n := len(r.List()); n != 32 {
+		t.Fatalf("want 32 entries, got %d", n)
+	}
+}
+
+func TestIsRetryableIn_ConsultsGivenRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	r.MustRegister(CodeInfo{Code: "lock_contention", Retryable: true})
+
+	err := Recode(nil, "lock_contention")
+	if IsRetryable(err) {
+		t.Fatalf("default registry should not know lock_contention")
+	}
+	if !IsRetryableIn(Join(BadRequest("x"), err), r) {
+		t.Fatalf("IsRetryableIn should find retryable branch via custom registry")
+	}
+	if IsRetryableIn(Unavailable("db"), r) {
+		t.Fatalf("custom registry without unavailable should not report retryable")
+	}
+	if IsRetryableIn(err, nil) {
+		t.Fatalf("nil registry must report false")
+	}
+}
+
+func TestIsRetryableIn_IgnoresJoinAggregate(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	r.MustRegister(CodeInfo{Code: CodeInterrupt, Retryable: true})
+
+	// The join reports CodeInterrupt for its foreign context.Canceled branch,
+	// but no node in the graph carries that code.
+	err := Join(NotFound("user", 1), context.Canceled)
+	if err.(coder).CodeVal() != CodeInterrupt {
+		t.Fatalf("aggregate: %q", err.(coder).CodeVal())
+	}
+	if IsRetryableIn(err, r) {
+		t.Fatalf("the join's aggregate code must not count as a branch")
+	}
+	if !IsRetryableIn(Join(NotFound("user", 1), Interrupt("x")), r) {
+		t.Fatalf("a branch carrying the code should still count")
+	}
+}
+
+func TestSeverity_String(t *testing.T) {
+	t.Parallel()
+
+	for s, want := range map[Severity]string{
+		SeverityUnspecified: "unspecified",
+		SeverityInfo:        "info",
+		SeverityWarning:     "warning",
+		SeverityError:       "error",
+		SeverityCritical:    "critical",
+	} {
+		if s.String() != want {
+			t.Fatalf("Severity(%d).String() = %q, want %q", s, s.String(), want)
+		}
+	}
+}
+
+func TestRegistry_ResolveWalksToNearestAncestor(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	r.MustRegister(CodeInfo{Code: CodeUnavailable, Retryable: true, HTTPStatus: 503})
+	r.MustRegister(CodeInfo{Code: "unavailable.db", Retryable: false, HTTPStatus: 500})
+
+	if info, ok := r.Resolve("unavailable.db.primary"); !ok || info.Code != "unavailable.db" {
+		t.Fatalf("Resolve(unavailable.db.primary) = %+v, %v; want unavailable.db", info, ok)
+	}
+	if info, ok := r.Resolve("unavailable.cache"); !ok || info.Code != CodeUnavailable {
+		t.Fatalf("Resolve(unavailable.cache) = %+v, %v; want unavailable", info, ok)
+	}
+	if _, ok := r.Lookup("unavailable.cache"); ok {
+		t.Fatalf("Lookup must be exact")
+	}
+	if _, ok := r.Resolve("timeout.x"); ok {
+		t.Fatalf("Resolve of unknown family should fail")
+	}
+	if r.Retryable("unavailable.db.primary") {
+		t.Fatalf("more specific registration should override ancestor retryability")
+	}
+	if !r.Retryable("unavailable.cache") {
+		t.Fatalf("unregistered child should inherit ancestor retryability")
+	}
+}
@@ -0,0 +1,189 @@
+// retry.go — classification-driven retries with backoff, budgets and clocks.
+//
+// Package retry re-runs an operation while its error is classified as
+// retryable (xgxerror.IsRetryable by default), keeping every attempt's error
+// instead of throwing the real cause away.
+//
+// Usage:
+//
+//	err := retry.Do(ctx, retry.Policy{MaxAttempts: 5, Jitter: 0.2}, func(ctx context.Context) error {
+//	    return callUpstream(ctx)
+//	})
+//
+// Semantics:
+//   - Backoff grows exponentially from InitialBackoff by Multiplier, capped at
+//     MaxBackoff; Jitter shaves a random fraction off each delay.
+//   - A "retry_after" field (time.Duration, e.g. set by httpx from the
+//     Retry-After header) raises the delay to at least that value.
+//   - MaxElapsed bounds the total time: a retry whose delay would overrun the
+//     budget is not attempted.
+//   - Every attempt's error is tagged with an "attempt" field (1-based) and
+//     all are returned via xgxerror.Join, newest first, so CodeOf and %v lead
+//     with the decisive error and %+v shows the whole history.
+//   - When ctx ends, Do returns Join(Interrupt or InterruptDeadline, attempts...).
+//   - The returned graph reports IsRetryable if any attempt did; callers that
+//     layer retries should rely on their own budget rather than re-retrying.
+package retry
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	xgxerror "github.com/tuliorib/xgx-error"
+)
+
+// Defaults applied to zero-valued Policy fields.
+const (
+	DefaultMaxAttempts    = 3
+	DefaultInitialBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff     = 10 * time.Second
+	DefaultMultiplier     = 2.0
+)
+
+// FieldAttempt tags each attempt's error with its 1-based attempt number.
+var FieldAttempt = xgxerror.FieldOf[int]("attempt")
+
+// fieldRetryAfter is the server-provided delay hint (see httpx.FieldRetryAfter).
+var fieldRetryAfter = xgxerror.FieldOf[time.Duration]("retry_after")
+
+// Clock abstracts time so tests can run without sleeping.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Policy configures Do. The zero value retries up to DefaultMaxAttempts times
+// with exponential backoff and no jitter.
+type Policy struct {
+	// MaxAttempts bounds the number of calls; <= 0 selects DefaultMaxAttempts.
+	MaxAttempts int
+	// InitialBackoff is the delay before the second attempt; 0 selects the default.
+	InitialBackoff time.Duration
+	// MaxBackoff caps a single computed delay; 0 selects the default.
+	MaxBackoff time.Duration
+	// Multiplier grows the delay per attempt; values < 1 select the default.
+	Multiplier float64
+	// Jitter in [0,1] removes up to that fraction of each delay at random.
+	Jitter float64
+	// MaxElapsed bounds the total time spent, including delays; 0 is unbounded.
+	MaxElapsed time.Duration
+	// Classifier decides whether an error is worth retrying; nil selects
+	// xgxerror.IsRetryable.
+	Classifier func(error) bool
+	// Clock supplies time; nil selects the wall clock.
+	Clock Clock
+}
+
+// Do calls fn until it succeeds, returns a non-retryable error, or the policy
+// or ctx runs out. See the package documentation for the returned error.
+func Do(ctx context.Context, p Policy, fn func(ctx context.Context) error) error {
+	p = p.withDefaults()
+	start := p.Clock.Now()
+
+	var attempts []error // newest first
+	for n := 1; ; n++ {
+		if ctx.Err() != nil {
+			return interrupted(ctx, attempts)
+		}
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		attempts = append([]error{tag(err, n)}, attempts...)
+
+		if ctx.Err() != nil {
+			return interrupted(ctx, attempts)
+		}
+		if n >= p.MaxAttempts || !p.Classifier(err) {
+			return xgxerror.Join(attempts...)
+		}
+		delay := p.backoff(n)
+		if hint, ok := retryAfter(err); ok && hint > delay {
+			delay = hint
+		}
+		if p.MaxElapsed > 0 && p.Clock.Now().Add(delay).Sub(start) > p.MaxElapsed {
+			return xgxerror.Join(attempts...)
+		}
+		select {
+		case <-ctx.Done():
+			return interrupted(ctx, attempts)
+		case <-p.Clock.After(delay):
+		}
+	}
+}
+
+func (p Policy) withDefaults() Policy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultMaxAttempts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = DefaultInitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultMaxBackoff
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = DefaultMultiplier
+	}
+	p.Jitter = min(max(p.Jitter, 0), 1)
+	if p.Classifier == nil {
+		p.Classifier = xgxerror.IsRetryable
+	}
+	if p.Clock == nil {
+		p.Clock = realClock{}
+	}
+	return p
+}
+
+// backoff returns the delay after attempt n (1-based).
+func (p Policy) backoff(n int) time.Duration {
+	d := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(n-1))
+	d = min(d, float64(p.MaxBackoff))
+	if p.Jitter > 0 {
+		d -= d * p.Jitter * rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+// tag attaches the attempt number. Foreign errors are adopted (see
+// xgxerror.Adopt), so neither Error() nor CodeOf changes.
+func tag(err error, n int) error {
+	if xe, ok := err.(xgxerror.Error); ok {
+		return FieldAttempt.Set(xe, n)
+	}
+	return xgxerror.Adopt(err, FieldAttempt.Key(), n)
+}
+
+// retryAfter returns the outermost retry_after hint in the graph.
+func retryAfter(err error) (time.Duration, bool) {
+	var (
+		d     time.Duration
+		found bool
+	)
+	xgxerror.Walk(err, func(e error) bool {
+		if xe, ok := e.(xgxerror.Error); ok {
+			d, found = fieldRetryAfter.Get(xe)
+		}
+		return !found
+	})
+	return d, found && d > 0
+}
+
+// interrupted reports ctx's end, joined with the attempts made so far.
+func interrupted(ctx context.Context, attempts []error) error {
+	var intr error
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		intr = xgxerror.InterruptDeadline("retry: deadline exceeded")
+	} else {
+		intr = xgxerror.Interrupt("retry: canceled")
+	}
+	return xgxerror.Join(append([]error{intr}, attempts...)...)
+}
@@ -0,0 +1,221 @@
+// retry_test.go — verification of classification-driven retries (no sleeping).
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	xgxerror "github.com/tuliorib/xgx-error"
+)
+
+// fakeClock advances instantly and records every requested delay.
+type fakeClock struct {
+	now    time.Time
+	delays []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.delays = append(c.delays, d)
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// attemptsOf returns the attempt numbers in the order they appear in err.
+func attemptsOf(err error) []int {
+	var out []int
+	xgxerror.Walk(err, func(e error) bool {
+		if xe, ok := e.(xgxerror.Error); ok {
+			if n, ok := FieldAttempt.Get(xe); ok {
+				out = append(out, n)
+			}
+		}
+		return true
+	})
+	return out
+}
+
+func TestDo_SucceedsAfterRetryableFailures(t *testing.T) {
+	t.Parallel()
+
+	clk := newFakeClock()
+	calls := 0
+	err := Do(context.Background(), Policy{MaxAttempts: 5, Clock: clk}, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return xgxerror.Unavailable("db")
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("want success on 3rd call, got err=%v calls=%d", err, calls)
+	}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}
+	if fmt.Sprint(clk.delays) != fmt.Sprint(want) {
+		t.Fatalf("backoff: want %v got %v", want, clk.delays)
+	}
+}
+
+func TestDo_ExhaustedKeepsEveryAttemptNewestFirst(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	err := Do(context.Background(), Policy{MaxAttempts: 3, Clock: newFakeClock()}, func(context.Context) error {
+		calls++
+		return xgxerror.Unavailable("db").With("call", calls)
+	})
+	if calls != 3 {
+		t.Fatalf("calls: %d", calls)
+	}
+	if got := attemptsOf(err); fmt.Sprint(got) != "[3 2 1]" {
+		t.Fatalf("attempts: %v", got)
+	}
+	if !xgxerror.HasCode(err, xgxerror.CodeUnavailable) {
+		t.Fatalf("classification lost: %v", err)
+	}
+}
+
+func TestDo_PermanentErrorStopsImmediately(t *testing.T) {
+	t.Parallel()
+
+	clk := newFakeClock()
+	calls := 0
+	err := Do(context.Background(), Policy{Clock: clk}, func(context.Context) error {
+		calls++
+		return xgxerror.NotFound("user", 1)
+	})
+	if calls != 1 || len(clk.delays) != 0 {
+		t.Fatalf("non-retryable error retried: calls=%d delays=%v", calls, clk.delays)
+	}
+	if xgxerror.CodeOf(err) != xgxerror.CodeNotFound || fmt.Sprint(attemptsOf(err)) != "[1]" {
+		t.Fatalf("unexpected result: %v", err)
+	}
+}
+
+func TestDo_CustomClassifierAndForeignErrors(t *testing.T) {
+	t.Parallel()
+
+	sentinel := errors.New("flaky")
+	calls := 0
+	err := Do(context.Background(), Policy{
+		MaxAttempts: 2,
+		Clock:       newFakeClock(),
+		Classifier:  func(err error) bool { return errors.Is(err, sentinel) },
+	}, func(context.Context) error {
+		calls++
+		return fmt.Errorf("call: %w", sentinel)
+	})
+	if calls != 2 || !errors.Is(err, sentinel) {
+		t.Fatalf("classifier ignored or cause lost: calls=%d err=%v", calls, err)
+	}
+	if got := fmt.Sprint(attemptsOf(err)); got != "[2 1]" {
+		t.Fatalf("foreign errors not tagged: %v", got)
+	}
+
+	// A native code behind a foreign wrapper stays the graph's code.
+	err = Do(context.Background(), Policy{Clock: newFakeClock()}, func(context.Context) error {
+		return fmt.Errorf("repo: %w", xgxerror.NotFound("user", 1))
+	})
+	if xgxerror.CodeOf(err) != xgxerror.CodeNotFound {
+		t.Fatalf("tagging changed CodeOf: %q", xgxerror.CodeOf(err))
+	}
+	if err.Error() != "repo: not_found: user not found" {
+		t.Fatalf("tagging changed the text: %q", err)
+	}
+	// A joined attempt error keeps every branch: tagging must not pick a code.
+	err = Do(context.Background(), Policy{MaxAttempts: 1, Clock: newFakeClock()}, func(context.Context) error {
+		return xgxerror.Join(xgxerror.Invalid("email", "format"), xgxerror.Unavailable("db"))
+	})
+	if got := xgxerror.DominantCode(err, nil); got != xgxerror.CodeUnavailable || fmt.Sprint(attemptsOf(err)) != "[1]" {
+		t.Fatalf("tagging changed the dominant code: %q attempts=%v", got, attemptsOf(err))
+	}
+}
+
+func TestDo_BackoffCapsAndJitterBounds(t *testing.T) {
+	t.Parallel()
+
+	p := Policy{InitialBackoff: time.Second, MaxBackoff: 3 * time.Second, Multiplier: 2}.withDefaults()
+	for n, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 3 * time.Second, 10: 3 * time.Second} {
+		if got := p.backoff(n); got != want {
+			t.Fatalf("backoff(%d): want %v got %v", n, want, got)
+		}
+	}
+	p.Jitter = 0.5
+	for range 100 {
+		if d := p.backoff(2); d < time.Second || d > 2*time.Second {
+			t.Fatalf("jittered delay out of bounds: %v", d)
+		}
+	}
+}
+
+func TestDo_HonorsRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	clk := newFakeClock()
+	_ = Do(context.Background(), Policy{MaxAttempts: 2, Clock: clk}, func(context.Context) error {
+		return xgxerror.TooManyRequests("api").With("retry_after", 5*time.Second)
+	})
+	if len(clk.delays) != 1 || clk.delays[0] != 5*time.Second {
+		t.Fatalf("retry_after not honored: %v", clk.delays)
+	}
+}
+
+func TestDo_MaxElapsedBudget(t *testing.T) {
+	t.Parallel()
+
+	clk := newFakeClock()
+	calls := 0
+	err := Do(context.Background(), Policy{
+		MaxAttempts:    10,
+		InitialBackoff: time.Second,
+		MaxElapsed:     4 * time.Second,
+		Clock:          clk,
+	}, func(context.Context) error {
+		calls++
+		return xgxerror.Unavailable("db")
+	})
+	// Delays 1s + 2s fit; the next (4s) would overrun the 4s budget.
+	if calls != 3 || fmt.Sprint(attemptsOf(err)) != "[3 2 1]" {
+		t.Fatalf("budget not enforced: calls=%d attempts=%v", calls, attemptsOf(err))
+	}
+}
+
+func TestDo_ContextEndReturnsInterruptJoinedWithAttempts(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := Do(ctx, Policy{MaxAttempts: 5, Clock: newFakeClock()}, func(context.Context) error {
+		calls++
+		if calls == 2 {
+			cancel()
+		}
+		return xgxerror.Unavailable("db")
+	})
+	if !xgxerror.IsInterrupt(err) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("want interrupt, got %v", err)
+	}
+	if !xgxerror.HasCode(err, xgxerror.CodeUnavailable) || fmt.Sprint(attemptsOf(err)) != "[2 1]" {
+		t.Fatalf("underlying attempts lost: %v", err)
+	}
+
+	dctx, dcancel := context.WithDeadline(context.Background(), time.Unix(0, 0))
+	defer dcancel()
+	err = Do(dctx, Policy{}, func(context.Context) error {
+		t.Fatalf("fn must not run after the deadline")
+		return nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) || !xgxerror.IsInterrupt(err) {
+		t.Fatalf("want deadline interrupt, got %v", err)
+	}
+}
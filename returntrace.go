@@ -0,0 +1,125 @@
+// returntrace.go — opt-in return traces: one call site per wrapping operation.
+//
+// A stack captured with WithStack shows where an error STARTED; a return trace
+// shows how it PROPAGATED back up the call stack. When enabled, each wrapping
+// operation records a single program counter (its caller), not a full stack:
+//   - package helpers: Wrap, Ctx, With, Recode, WithStack, WithStackSkip,
+//     WithPinned, WithDetail, CtxFrom and TypedField.Set;
+//   - fluent methods: MsgReplace, MsgAppend, Ctx, CtxBound, With, Code,
+//     WithStack, WithStackSkip.
+//
+// Semantics:
+//   - Off by default; SetReturnTraces(true) enables recording process-wide.
+//     While off, a wrap costs one atomic load. Entries already recorded stay.
+//   - Entries are ordered OLDEST FIRST: the first is the wrap closest to the
+//     origin, the last is the most recent (outermost) one.
+//   - Traces live on native nodes and are copied on write like context.
+//     Wrapping a foreign error starts a new node with its own trace; %+v
+//     renders each node's trace after its stack.
+//   - Constructors (NotFound, Internal, ...) do not record; pair them with
+//     WithStack when the origin itself matters.
+//   - Frames resolve lazily (ReturnTrace, %+v) and follow the package
+//     StackPolicy's path rewriting.
+package xgxerror
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// returnTraces gates recording; see SetReturnTraces.
+var returnTraces atomic.Bool
+
+// SetReturnTraces turns return-trace recording on or off. Safe for
+// concurrent use.
+func SetReturnTraces(on bool) { returnTraces.Store(on) }
+
+// ReturnTracesEnabled reports whether return traces are being recorded.
+func ReturnTracesEnabled() bool { return returnTraces.Load() }
+
+// ReturnTrace returns the resolved return trace of the outermost native error
+// in err's single-cause chain, oldest entry first. It returns nil if there is
+// none (traces off, no native node, or a Join before the first native node).
+func ReturnTrace(err error) []Frame {
+	for err != nil {
+		switch e := err.(type) {
+		case *failureErr:
+			return traceFrames(e.trace)
+		case *defectErr:
+			return traceFrames(e.trace)
+		case *interruptErr:
+			return traceFrames(e.trace)
+		}
+		u, ok := err.(singleUnwrapper)
+		if !ok {
+			return nil
+		}
+		err = u.Unwrap()
+	}
+	return nil
+}
+
+// appendTrace returns t plus the PC of the caller of appendTrace's caller,
+// skipping skip further frames. It never writes into t's backing array, so
+// clones sharing t stay isolated. No-op while return traces are off.
+func appendTrace(t []uintptr, skip int) []uintptr {
+	if !returnTraces.Load() {
+		return t
+	}
+	var pc [1]uintptr
+	// Hide runtime.Callers, appendTrace and its caller.
+	if runtime.Callers(3+skip, pc[:]) == 0 {
+		return t
+	}
+	return append(t[:len(t):len(t)], pc[0])
+}
+
+// retrace re-points the newest entry of e (freshly returned by a fluent
+// method) at the caller of retrace's caller, skipping skip further frames.
+// Package helpers delegate to fluent methods, which would otherwise record
+// the helper's own line.
+func retrace(e Error, skip int) Error {
+	if !returnTraces.Load() {
+		return e
+	}
+	var tp *[]uintptr
+	switch n := e.(type) {
+	case *failureErr:
+		tp = &n.trace
+	case *defectErr:
+		tp = &n.trace
+	case *interruptErr:
+		tp = &n.trace
+	default:
+		return e
+	}
+	t := *tp
+	if len(t) == 0 {
+		return e
+	}
+	var pc [1]uintptr
+	if runtime.Callers(3+skip, pc[:]) == 0 {
+		return e
+	}
+	// Copy rather than overwrite in place: the slice may still be shared if
+	// recording was switched on between the method call and this one.
+	*tp = append(t[:len(t)-1:len(t)-1], pc[0])
+	return e
+}
+
+// traceFrames resolves each PC to exactly one frame (the call site; inlined
+// callers are not expanded) with paths rewritten per the package policy.
+func traceFrames(pcs []uintptr) []Frame {
+	if len(pcs) == 0 {
+		return nil
+	}
+	p := loadStackPolicy()
+	out := make([]Frame, 0, len(pcs))
+	for _, pc := range pcs {
+		fr, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		f := Frame{PC: fr.PC, File: fr.File, Line: fr.Line, Function: fr.Function}
+		f.File = p.rewritePath(f)
+		out = append(out, f)
+	}
+	return out
+}
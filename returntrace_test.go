@@ -0,0 +1,151 @@
+// returntrace_test.go — verification of opt-in per-wrap return traces.
+package xgxerror
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// enableReturnTraces turns recording on for one test and restores it after.
+func enableReturnTraces(t *testing.T) {
+	t.Helper()
+	prev := ReturnTracesEnabled()
+	SetReturnTraces(true)
+	t.Cleanup(func() { SetReturnTraces(prev) })
+}
+
+// traceFuncs returns the short function name of every trace entry.
+func traceFuncs(err error) []string {
+	var out []string
+	for _, fr := range ReturnTrace(err) {
+		out = append(out, fr.Function[strings.LastIndex(fr.Function, ".")+1:])
+	}
+	return out
+}
+
+//go:noinline
+func traceRepo() error { return NotFound("user", 7).With("table", "users") }
+
+//go:noinline
+func traceService() error { return Wrap(traceRepo(), "load user") }
+
+//go:noinline
+func traceHandler() error { return Ctx(traceService(), "", "route", "/users/7") }
+
+func TestReturnTrace_RecordsEachWrapOldestFirst(t *testing.T) {
+	// Not parallel: mutates the package-level toggle.
+	enableReturnTraces(t)
+
+	err := traceHandler()
+	got := strings.Join(traceFuncs(err), " ")
+	if got != "traceRepo traceService traceHandler" {
+		t.Fatalf("trace: %q", got)
+	}
+	for _, fr := range ReturnTrace(err) {
+		if filepath.Base(fr.File) != "returntrace_test.go" || fr.Line == 0 {
+			t.Fatalf("frame should point at the call site: %+v", fr)
+		}
+	}
+}
+
+func TestReturnTrace_HelpersRecordTheirCaller(t *testing.T) {
+	// Not parallel: mutates the package-level toggle.
+	enableReturnTraces(t)
+
+	base := errors.New("io")
+	cases := map[string]error{
+		"Wrap/foreign": Wrap(base, "read"),
+		"Wrap/nil":     Wrap(nil, "read"),
+		"With":         With(base, "k", 1),
+		"Recode":       Recode(Conflict("dup"), CodeUnavailable),
+		"Ctx":          Ctx(base, "read"),
+		"WithStack":    WithStack(Invalid("name", "empty")),
+		"Set":          FieldOf[int]("n").Set(BadRequest("x"), 1),
+		"fluent":       Unavailable("db").MsgAppend("primary").CtxBound("", 1, "a", 1).Code(CodeTimeout),
+	}
+	for name, err := range cases {
+		want := 1
+		if name == "fluent" {
+			want = 3
+		}
+		fs := ReturnTrace(err)
+		if len(fs) != want {
+			t.Fatalf("%s: want %d entries, got %+v", name, want, fs)
+		}
+		for _, fr := range fs {
+			if !strings.HasSuffix(fr.Function, ".TestReturnTrace_HelpersRecordTheirCaller") {
+				t.Fatalf("%s: recorded %s, want the test function", name, fr.Function)
+			}
+		}
+	}
+}
+
+func TestReturnTrace_OffByDefaultAndCopyOnWrite(t *testing.T) {
+	// Not parallel: mutates the package-level toggle.
+	prev := ReturnTracesEnabled()
+	t.Cleanup(func() { SetReturnTraces(prev) })
+	if prev {
+		t.Fatalf("return traces must be off by default")
+	}
+
+	if fs := ReturnTrace(traceHandler()); fs != nil {
+		t.Fatalf("no trace expected while off: %+v", fs)
+	}
+
+	SetReturnTraces(true)
+	e1 := Wrap(Conflict("dup"), "a")
+	e2 := e1.Ctx("", "k", 1)
+	e3 := e1.With("k", 2)
+	if len(ReturnTrace(e1)) != 1 || len(ReturnTrace(e2)) != 2 || len(ReturnTrace(e3)) != 2 {
+		t.Fatalf("wraps must not share or mutate traces: %d %d %d",
+			len(ReturnTrace(e1)), len(ReturnTrace(e2)), len(ReturnTrace(e3)))
+	}
+	if ReturnTrace(e2)[1].Line == ReturnTrace(e3)[1].Line {
+		t.Fatalf("sibling wraps should record their own call sites")
+	}
+
+	SetReturnTraces(false)
+	if len(ReturnTrace(e2.With("k", 3))) != 2 {
+		t.Fatalf("existing entries survive when recording is switched off")
+	}
+}
+
+func TestReturnTrace_ThroughForeignWrappersAndFormatting(t *testing.T) {
+	// Not parallel: mutates the package-level toggle.
+	enableReturnTraces(t)
+
+	inner := traceService()
+	outer := fmt.Errorf("handler: %w", inner)
+	if got := strings.Join(traceFuncs(outer), " "); got != "traceRepo traceService" {
+		t.Fatalf("foreign wrapper should expose the native trace: %q", got)
+	}
+	if ReturnTrace(Join(inner, inner)) != nil || ReturnTrace(nil) != nil {
+		t.Fatalf("joins and nil carry no trace")
+	}
+
+	out := fmt.Sprintf("%+v", Wrap(Internal(inner), "top"))
+	if strings.Count(out, "\ntrace:") != 2 || !strings.Contains(out, ".traceService ") {
+		t.Fatalf("%%+v should render a trace per native node:\n%s", out)
+	}
+	if strings.Contains(fmt.Sprintf("%+v", NotFound("user", 1)), "trace:") {
+		t.Fatalf("untraced errors must omit the section")
+	}
+}
+
+func BenchmarkWrap_ReturnTraces(b *testing.B) {
+	base := NotFound("user", 1)
+	for _, on := range []bool{false, true} {
+		b.Run(fmt.Sprintf("on=%v", on), func(b *testing.B) {
+			prev := ReturnTracesEnabled()
+			SetReturnTraces(on)
+			defer SetReturnTraces(prev)
+			b.ReportAllocs()
+			for b.Loop() {
+				_ = Wrap(base, "load", "id", 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+// slog.go — log/slog structured rendering for xgx-error core.
+//
+// Native errors and Join containers implement slog.LogValuer, so
+// slog.Any("err", err) emits a structured group instead of a flat string or a
+// multi-line %+v blob:
+//
+//	err.kind=failure err.code=not_found err.msg="user not found"
+//	err.ctx.entity=user err.ctx.id=42
+//	err.cause.kind=foreign err.cause.msg="sql: no rows"
+//
+// Behavior:
+//   - Join containers render as {kind=join, errors={0={...}, 1={...}}}.
+//   - Foreign errors render as {kind=foreign, msg=Error()} and still recurse
+//     into their causes, so codes behind fmt.Errorf("%w") stay visible.
+//   - Sensitive fields are redacted (see redact.go).
+//   - Typed details (see WithDetail) render under "details" as a list of
+//     values, oldest first; the handler decides how to encode them.
+//   - Stacks are omitted by default; LogValue with LogOptions.Stack includes
+//     them (the slogx handler exposes this as an option). Frames shared with
+//     the cause's stack are elided and counted in "stack_common".
+//
+// Rationale:
+//   - slog is stdlib; implementing LogValuer adds no dependency or policy.
+//     Handler-level behavior (which attrs to expand, stack toggles) lives in
+//     the slogx subpackage.
+package xgxerror
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// defaultLogMaxDepth bounds nested cause rendering in log output.
+const defaultLogMaxDepth = 32
+
+// LogOptions controls LogValue rendering.
+type LogOptions struct {
+	// Stack includes captured stack frames as a list of "func file:line".
+	Stack bool
+	// MaxDepth bounds nested cause/child rendering; <= 0 selects a default.
+	MaxDepth int
+}
+
+// LogValue renders err as a structured slog group using opts.
+// LogValue(nil, ...) returns an empty group.
+func LogValue(err error, opts LogOptions) slog.Value {
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = defaultLogMaxDepth
+	}
+	return logValue(err, opts, 0)
+}
+
+func logValue(err error, opts LogOptions, depth int) slog.Value {
+	if err == nil {
+		return slog.GroupValue()
+	}
+	n := Inspect(err)
+	attrs := make([]slog.Attr, 0, 6)
+	attrs = append(attrs, slog.String("kind", n.Kind.String()))
+	if n.Code != "" {
+		attrs = append(attrs, slog.String("code", string(n.Code)))
+	}
+	if n.Kind != KindJoin {
+		attrs = append(attrs, slog.String("msg", n.Msg))
+	}
+	if len(n.Fields) > 0 {
+		ctx := make([]slog.Attr, 0, len(n.Fields))
+		for _, f := range n.Fields {
+			if f.Key == "" {
+				continue
+			}
+			ctx = append(ctx, slog.Any(f.Key, f.Redacted().Val))
+		}
+		if len(ctx) > 0 {
+			attrs = append(attrs, slog.Attr{Key: "ctx", Value: slog.GroupValue(ctx...)})
+		}
+	}
+	if len(n.Details) > 0 {
+		attrs = append(attrs, slog.Any("details", n.Details))
+	}
+	if depth < opts.MaxDepth {
+		if n.Cause != nil {
+			attrs = append(attrs, slog.Attr{Key: "cause", Value: logValue(n.Cause, opts, depth+1)})
+		}
+		if len(n.Errors) > 0 {
+			kids := make([]slog.Attr, 0, len(n.Errors))
+			for i, c := range n.Errors {
+				if c != nil {
+					kids = append(kids, slog.Attr{Key: strconv.Itoa(i), Value: logValue(c, opts, depth+1)})
+				}
+			}
+			attrs = append(attrs, slog.Attr{Key: "errors", Value: slog.GroupValue(kids...)})
+		}
+	}
+	if fs := n.Stack.Filtered(); opts.Stack && len(fs) > 0 {
+		common := 0
+		if depth < opts.MaxDepth {
+			common = n.Stack.CommonSuffix(n.CauseStack())
+		}
+		frames := make([]string, 0, len(fs)-common)
+		for _, fr := range fs[:len(fs)-common] {
+			frames = append(frames, fmt.Sprintf("%s %s:%d", fr.Function, fr.File, fr.Line))
+		}
+		attrs = append(attrs, slog.Any("stack", frames))
+		if common > 0 {
+			attrs = append(attrs, slog.Int("stack_common", common))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer (no stack; see LogValue for options).
+func (e *failureErr) LogValue() slog.Value { return LogValue(e, LogOptions{}) }
+
+// LogValue implements slog.LogValuer (no stack; see LogValue for options).
+func (e *defectErr) LogValue() slog.Value { return LogValue(e, LogOptions{}) }
+
+// LogValue implements slog.LogValuer.
+func (e *interruptErr) LogValue() slog.Value { return LogValue(e, LogOptions{}) }
+
+// LogValue implements slog.LogValuer; children render under "errors".
+func (m *multi) LogValue() slog.Value { return LogValue(m, LogOptions{}) }
+
+// LogValue implements slog.LogValuer and always renders RedactedText.
+func (s SecretValue) LogValue() slog.Value { return slog.StringValue(RedactedText) }
+
+var (
+	_ slog.LogValuer = (*failureErr)(nil)
+	_ slog.LogValuer = (*defectErr)(nil)
+	_ slog.LogValuer = (*interruptErr)(nil)
+	_ slog.LogValuer = (*multi)(nil)
+	_ slog.LogValuer = SecretValue{}
+)
@@ -0,0 +1,165 @@
+// slog_test.go — verification of slog.LogValuer rendering.
+package xgxerror
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// logJSON logs err under "err" with a JSON handler and returns the decoded "err" object.
+func logJSON(t *testing.T, err error) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("boom", "err", err)
+	if strings.Count(buf.String(), "\n") != 1 {
+		t.Fatalf("expected exactly one JSON log line, got:\n%s", buf.String())
+	}
+	var rec map[string]any
+	if e := json.Unmarshal(buf.Bytes(), &rec); e != nil {
+		t.Fatalf("invalid JSON log line: %v\n%s", e, buf.String())
+	}
+	obj, ok := rec["err"].(map[string]any)
+	if !ok {
+		t.Fatalf("err attr is not a group: %#v", rec["err"])
+	}
+	return obj
+}
+
+func TestLogValue_FailureGroupWithOrderedCtxAndCause(t *testing.T) {
+	t.Parallel()
+
+	err := Wrap(fmt.Errorf("sql: no rows"), "lookup failed", "table", "users", "id", 42)
+	obj := logJSON(t, err)
+
+	if obj["kind"] != "failure" || obj["code"] != "internal" || obj["msg"] != "lookup failed" {
+		t.Fatalf("unexpected header: %v", obj)
+	}
+	ctx := obj["ctx"].(map[string]any)
+	if ctx["table"] != "users" || ctx["id"] != float64(42) {
+		t.Fatalf("unexpected ctx: %v", ctx)
+	}
+	cause := obj["cause"].(map[string]any)
+	if cause["kind"] != "foreign" || cause["msg"] != "sql: no rows" {
+		t.Fatalf("unexpected cause: %v", cause)
+	}
+	if _, hasStack := obj["stack"]; hasStack {
+		t.Fatalf("stack must be omitted by default")
+	}
+}
+
+func TestLogValue_CtxOrderPreservedInGroup(t *testing.T) {
+	t.Parallel()
+
+	v := BadRequest("x").Ctx("", "k3", 3, "k1", 1, "k2", 2).(*failureErr).LogValue()
+	var ctx []slog.Attr
+	for _, a := range v.Group() {
+		if a.Key == "ctx" {
+			ctx = a.Value.Group()
+		}
+	}
+	if len(ctx) != 3 || ctx[0].Key != "k3" || ctx[1].Key != "k1" || ctx[2].Key != "k2" {
+		t.Fatalf("ctx order not preserved: %v", ctx)
+	}
+}
+
+func TestLogValue_JoinRendersIndexedChildren(t *testing.T) {
+	t.Parallel()
+
+	obj := logJSON(t, Join(Invalid("email", "format"), Defect(errors.New("nil map"))))
+	if obj["kind"] != "join" {
+		t.Fatalf("want join group, got %v", obj)
+	}
+	kids := obj["errors"].(map[string]any)
+	first := kids["0"].(map[string]any)
+	second := kids["1"].(map[string]any)
+	if first["code"] != "invalid" || second["code"] != "defect" {
+		t.Fatalf("unexpected children: %v", kids)
+	}
+}
+
+func TestLogValue_InterruptAndDefect(t *testing.T) {
+	t.Parallel()
+
+	obj := logJSON(t, InterruptDeadline("late"))
+	if obj["kind"] != "interrupt" || obj["code"] != "interrupt" || obj["msg"] != "late" {
+		t.Fatalf("unexpected interrupt group: %v", obj)
+	}
+	if cause := obj["cause"].(map[string]any); cause["msg"] != "context deadline exceeded" {
+		t.Fatalf("unexpected interrupt cause: %v", cause)
+	}
+
+	obj = logJSON(t, Defect(errors.New("bug")))
+	if obj["kind"] != "defect" || obj["code"] != "defect" {
+		t.Fatalf("unexpected defect group: %v", obj)
+	}
+}
+
+func TestLogValue_RedactsSensitiveFields(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	err := Unauthorized("denied").Ctx("", "password", "hunter2", "email", Secret("a@example.com"))
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("x", "err", err, "direct", Secret("raw"))
+	out := buf.String()
+	notContains(t, out, "hunter2", "a@example.com", `"raw"`)
+	containsAll(t, out, RedactedText)
+}
+
+func TestLogValue_StackOptionAndDepthBound(t *testing.T) {
+	t.Parallel()
+
+	e := Internal(errors.New("x"))
+	var hasStack bool
+	for _, a := range LogValue(e, LogOptions{Stack: true}).Group() {
+		if a.Key == "stack" {
+			frames, _ := a.Value.Any().([]string)
+			hasStack = len(frames) > 0 && strings.Contains(strings.Join(frames, "\n"), "TestLogValue_StackOptionAndDepthBound")
+		}
+	}
+	if !hasStack {
+		t.Fatalf("LogOptions.Stack should include frames covering the call site")
+	}
+
+	nested := Internal(Defect(errors.New("inner")))
+	var common int64
+	for _, a := range LogValue(nested, LogOptions{Stack: true}).Group() {
+		if a.Key == "stack_common" {
+			common = a.Value.Int64()
+		}
+	}
+	if common == 0 {
+		t.Fatalf("nested stacks should report stack_common")
+	}
+
+	deep := error(BadRequest("leaf"))
+	for i := 0; i < 5; i++ {
+		deep = Internal(deep)
+	}
+	depth := 0
+	v := LogValue(deep, LogOptions{MaxDepth: 2})
+	for {
+		var next *slog.Value
+		for _, a := range v.Group() {
+			if a.Key == "cause" {
+				val := a.Value
+				next = &val
+			}
+		}
+		if next == nil {
+			break
+		}
+		depth++
+		v = *next
+	}
+	if depth != 2 {
+		t.Fatalf("MaxDepth=2 should render 2 nested causes, got %d", depth)
+	}
+	if got := LogValue(nil, LogOptions{}); len(got.Group()) != 0 {
+		t.Fatalf("LogValue(nil) should be an empty group")
+	}
+}
@@ -0,0 +1,98 @@
+// slogx.go — error-aware slog.Handler middleware.
+//
+// Package slogx wraps any slog.Handler and expands error-valued attributes
+// into structured groups using xgxerror.LogValue, so that foreign errors (and
+// native ones, with optional stacks) log as code/msg/ctx/cause trees rather
+// than flat strings.
+//
+// Usage:
+//
+//	logger := slog.New(slogx.NewHandler(slog.NewJSONHandler(os.Stdout, nil), &slogx.Options{Stack: true}))
+//	logger.Error("fetch failed", "err", err)
+//
+// Semantics:
+//   - Every attribute whose value is an error (at any group depth, including
+//     attributes added via Logger.With) is replaced by its structured group.
+//   - Non-error attributes pass through untouched.
+//   - Enabled/WithGroup delegate to the wrapped handler.
+package slogx
+
+import (
+	"context"
+	"log/slog"
+
+	xgxerror "github.com/tuliorib/xgx-error"
+)
+
+// Options configures the handler. A nil *Options selects the defaults.
+type Options struct {
+	// Stack includes captured stack frames for native errors.
+	Stack bool
+	// MaxDepth bounds nested cause rendering; <= 0 selects the core default.
+	MaxDepth int
+}
+
+// Handler is a slog.Handler middleware that expands error attributes.
+type Handler struct {
+	next slog.Handler
+	opts xgxerror.LogOptions
+}
+
+// NewHandler returns a Handler that expands error attributes before
+// delegating to next.
+func NewHandler(next slog.Handler, opts *Options) *Handler {
+	h := &Handler{next: next}
+	if opts != nil {
+		h.opts = xgxerror.LogOptions{Stack: opts.Stack, MaxDepth: opts.MaxDepth}
+	}
+	return h
+}
+
+// Enabled reports whether the wrapped handler handles records at level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle expands error attributes in r and passes a new record to the wrapped handler.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(h.expand(a))
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+// WithAttrs expands error attributes once and delegates.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		expanded[i] = h.expand(a)
+	}
+	return &Handler{next: h.next.WithAttrs(expanded), opts: h.opts}
+}
+
+// WithGroup delegates to the wrapped handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name), opts: h.opts}
+}
+
+// expand replaces error values with structured groups, recursing into groups.
+func (h *Handler) expand(a slog.Attr) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindAny, slog.KindLogValuer:
+		if err, ok := a.Value.Any().(error); ok && err != nil {
+			return slog.Attr{Key: a.Key, Value: xgxerror.LogValue(err, h.opts)}
+		}
+	case slog.KindGroup:
+		group := a.Value.Group()
+		expanded := make([]slog.Attr, len(group))
+		for i, g := range group {
+			expanded[i] = h.expand(g)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(expanded...)}
+	}
+	return a
+}
+
+var _ slog.Handler = (*Handler)(nil)
@@ -0,0 +1,99 @@
+// slogx_test.go — verification of the error-aware slog handler middleware.
+package slogx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	xgxerror "github.com/tuliorib/xgx-error"
+)
+
+func newLogger(opts *Options) (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return slog.New(NewHandler(slog.NewJSONHandler(&buf, nil), opts)), &buf
+}
+
+func decode(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var rec map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	return rec
+}
+
+func TestHandler_ExpandsForeignErrorsWithNativeCauses(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newLogger(nil)
+	err := fmt.Errorf("handler: %w", xgxerror.NotFound("user", 42))
+	logger.Error("request failed", "err", err, "path", "/users/42")
+
+	rec := decode(t, buf)
+	if rec["path"] != "/users/42" || rec["msg"] != "request failed" {
+		t.Fatalf("non-error attrs altered: %v", rec)
+	}
+	obj := rec["err"].(map[string]any)
+	if obj["kind"] != "foreign" {
+		t.Fatalf("foreign error should expand to a group: %v", obj)
+	}
+	cause := obj["cause"].(map[string]any)
+	if cause["code"] != "not_found" {
+		t.Fatalf("native cause not expanded: %v", cause)
+	}
+}
+
+func TestHandler_StackOption(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		opts *Options
+		want bool
+	}{
+		{nil, false},
+		{&Options{Stack: true}, true},
+	} {
+		logger, buf := newLogger(tc.opts)
+		logger.Error("x", "err", xgxerror.Internal(errors.New("db")))
+		_, has := decode(t, buf)["err"].(map[string]any)["stack"]
+		if has != tc.want {
+			t.Fatalf("opts=%+v: stack present=%v, want %v", tc.opts, has, tc.want)
+		}
+	}
+}
+
+func TestHandler_WithAttrsAndGroups(t *testing.T) {
+	t.Parallel()
+
+	logger, buf := newLogger(&Options{Stack: true})
+	logger = logger.With("base", xgxerror.Unavailable("db"))
+	logger.WithGroup("req").Error("x", slog.Group("inner", "err", errors.New("plain")))
+
+	rec := decode(t, buf)
+	base := rec["base"].(map[string]any)
+	if base["code"] != "unavailable" {
+		t.Fatalf("WithAttrs error not expanded: %v", base)
+	}
+	inner := rec["req"].(map[string]any)["inner"].(map[string]any)["err"].(map[string]any)
+	if inner["kind"] != "foreign" || inner["msg"] != "plain" {
+		t.Fatalf("nested group error not expanded: %v", inner)
+	}
+}
+
+func TestHandler_EnabledDelegates(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	h := NewHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}), nil)
+	if h.Enabled(context.Background(), slog.LevelInfo) {
+		t.Fatalf("Enabled should delegate to wrapped handler level")
+	}
+	if !h.Enabled(context.Background(), slog.LevelError) {
+		t.Fatalf("Enabled(Error) should be true")
+	}
+}
@@ -5,7 +5,7 @@
 //     accurate frame resolution (handles inlining correctly).
 //   - Minimal policy: no global toggles here; callers opt in via WithStack*.
 //   - Pragmatic performance: bounded depth, cheap defaults, allocate only when
-//     capture is requested.
+//     capture is requested, and symbolize only when frames are read.
 //
 // Skip model (centralized):
 //   - captureStack accounts for its own internal frames:
@@ -25,12 +25,22 @@
 //   Defect(...) → captureStackDefault(0) → captureStack → runtime.Callers
 //     • baseSkip (3) hides runtime.Callers, captureStack, captureStackDefault.
 //
+// Lazy symbolization:
+//   - Capture stores only the raw program counters. Most errors are created,
+//     classified and discarded without ever being printed, so resolving
+//     function/file/line (runtime.CallersFrames) is deferred until Frames,
+//     All or Len is called, then cached once per captured stack.
+//   - Stack is a small immutable value; copies share the captured PCs and the
+//     resolved frames, so clone() stays a shallow copy.
+//
 // Notes:
 //   - We keep depth modest (defaultMaxDepth) and resolve frames via CallersFrames.
 package xgxerror
 
 import (
+	"iter"
 	"runtime"
+	"sync"
 )
 
 // Frame represents a single call site in a stack trace.
@@ -41,8 +51,101 @@ type Frame struct {
 	Function string  // fully-qualified function name (pkg.Func or method)
 }
 
-// Stack is a slice of Frames from most recent call outward.
-type Stack []Frame
+// Stack is a captured call stack, most recent call first. The zero value is
+// an empty stack. Frames are resolved on first access and cached.
+type Stack struct {
+	st  *stackState
+	pol *StackPolicy // rendering override; nil selects the package default
+}
+
+// stackState is shared by all copies of a Stack.
+type stackState struct {
+	pcs    []uintptr
+	once   sync.Once
+	frames []Frame
+}
+
+// StackFromFrames builds an already-resolved Stack (e.g., for decoders that
+// restore frames without program counters). The frames are copied.
+func StackFromFrames(frames []Frame) Stack {
+	if len(frames) == 0 {
+		return Stack{}
+	}
+	st := &stackState{frames: make([]Frame, len(frames))}
+	copy(st.frames, frames)
+	st.pcs = make([]uintptr, len(frames))
+	for i, fr := range frames {
+		st.pcs[i] = fr.PC
+	}
+	st.once.Do(func() {}) // already resolved
+	return Stack{st: st}
+}
+
+// Empty reports whether no stack was captured. It never resolves frames.
+func (s Stack) Empty() bool { return s.st == nil }
+
+// PCs returns a copy of the captured program counters.
+func (s Stack) PCs() []uintptr {
+	if s.st == nil {
+		return nil
+	}
+	out := make([]uintptr, len(s.st.pcs))
+	copy(out, s.st.pcs)
+	return out
+}
+
+// Frames returns a copy of the resolved frames (nil for an empty stack).
+// Inlined calls expand into their own frames, so there may be more frames
+// than program counters.
+func (s Stack) Frames() []Frame {
+	fs := s.resolved()
+	if len(fs) == 0 {
+		return nil
+	}
+	out := make([]Frame, len(fs))
+	copy(out, fs)
+	return out
+}
+
+// All iterates over the resolved frames without copying them.
+func (s Stack) All() iter.Seq2[int, Frame] {
+	return func(yield func(int, Frame) bool) {
+		for i, fr := range s.resolved() {
+			if !yield(i, fr) {
+				return
+			}
+		}
+	}
+}
+
+// Len returns the number of resolved frames.
+func (s Stack) Len() int { return len(s.resolved()) }
+
+// resolved symbolizes the PCs once and returns the cached frames.
+func (s Stack) resolved() []Frame {
+	if s.st == nil {
+		return nil
+	}
+	st := s.st
+	st.once.Do(func() {
+		frames := runtime.CallersFrames(st.pcs)
+		out := make([]Frame, 0, len(st.pcs))
+		for {
+			fr, more := frames.Next()
+			out = append(out, Frame{
+				PC:       fr.PC,
+				File:     fr.File,
+				Line:     fr.Line,
+				Function: fr.Function,
+			})
+			if !more {
+				break
+			}
+		}
+		st.frames = out
+	})
+	return st.frames
+}
 
 const (
 	// defaultMaxDepth captures meaningful context without excessive work
@@ -52,40 +155,80 @@ const (
 
 // captureStack captures a stack. The function accounts for its own internal frames:
 // +1 for runtime.Callers, +1 for captureStack, and +1 for captureStackDefault.
-// Callers pass only their extra skip (skipExtra).
+// Callers pass only their extra skip (skipExtra). Only PCs are recorded here;
+// symbolization happens lazily (see Stack.Frames).
 func captureStack(skipExtra, maxDepth int) Stack {
 	if maxDepth <= 0 {
 		maxDepth = defaultMaxDepth
 	}
-	pc := make([]uintptr, maxDepth)
+	var buf [defaultMaxDepth]uintptr
+	pc := buf[:]
+	if maxDepth > defaultMaxDepth {
+		pc = make([]uintptr, maxDepth)
+	}
+	pc = pc[:maxDepth]
 
 	// See header notes: hide runtime.Callers, captureStack, captureStackDefault.
 	const baseSkip = 3
 	n := runtime.Callers(baseSkip+skipExtra, pc)
 	if n == 0 {
-		return nil
+		return Stack{}
 	}
-	pc = pc[:n]
-
-	frames := runtime.CallersFrames(pc)
-	out := make(Stack, 0, n)
-	for {
-		fr, more := frames.Next()
-		out = append(out, Frame{
-			PC:       fr.PC,
-			File:     fr.File,
-			Line:     fr.Line,
-			Function: fr.Function,
-		})
-		if !more {
+	// Keep exactly n PCs so the scratch buffer does not escape.
+	st := &stackState{pcs: make([]uintptr, n)}
+	copy(st.pcs, pc[:n])
+	return Stack{st: st}
+}
+
+// captureStackDefault captures a stack with the default policy's
+// CaptureDepth (defaultMaxDepth when unset), skipping only the additional
+// frames requested by the caller (skipExtra).
+func captureStackDefault(skipExtra int) Stack {
+	return captureStack(skipExtra, loadStackPolicy().CaptureDepth)
+}
+
+// CommonSuffix returns how many trailing rendered frames (see Filtered) s
+// shares with other. Nested layers captured on the same goroutine typically
+// share everything below their call sites; renderers elide that suffix.
+func (s Stack) CommonSuffix(other Stack) int {
+	if s.Empty() || other.Empty() {
+		return 0
+	}
+	return commonFrameSuffix(s.Filtered(), other.Filtered())
+}
+
+func commonFrameSuffix(a, b []Frame) int {
+	n := 0
+	for i, j := len(a)-1, len(b)-1; i >= 0 && j >= 0; i, j = i-1, j-1 {
+		if a[i].Function != b[j].Function || a[i].File != b[j].File || a[i].Line != b[j].Line {
 			break
 		}
+		n++
 	}
-	return out
+	return n
 }
 
-// captureStackDefault captures a stack with a conservative default depth,
-// skipping only the additional frames requested by the caller (skipExtra).
-func captureStackDefault(skipExtra int) Stack {
-	return captureStack(skipExtra, defaultMaxDepth)
+// causeStack returns the nearest non-empty stack along the chain of native
+// nodes starting at err (err included). Foreign nodes and Join containers end
+// the search, since %+v does not render stacks behind them.
+func causeStack(err error) Stack {
+	for depth := 0; err != nil && depth < defaultMaxDepth; depth++ {
+		switch e := err.(type) {
+		case *failureErr:
+			if !e.stk.Empty() {
+				return e.stk
+			}
+			err = e.cause
+		case *defectErr:
+			if !e.stk.Empty() {
+				return e.stk
+			}
+			err = e.cause
+		case *interruptErr:
+			err = e.cause
+		default:
+			return Stack{}
+		}
+	}
+	return Stack{}
 }
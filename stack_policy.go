@@ -0,0 +1,204 @@
+// stack_policy.go — filtering, trimming and path shortening for stacks.
+//
+// A StackPolicy shapes how captured stacks are RENDERED: it drops frames by
+// function prefix (runtime internals, net/http plumbing, middleware), rewrites
+// file paths so build-machine layouts do not leak, and caps the number of
+// frames shown. Capture itself is unaffected except for CaptureDepth.
+//
+// Scope:
+//   - Package default: SetDefaultStackPolicy (zero value = render everything
+//     with absolute paths, the historical behavior).
+//   - Per call: WithStackPolicy(err, p) / Stack.WithPolicy(p) override the
+//     default for one error's stack.
+//   - Applied by %+v (formatVerbose) and by exporters (slog, jsonx), which all
+//     read Stack.Filtered. Stack.Frames/All stay raw for programmatic use.
+package xgxerror
+
+import (
+	"path"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// PathMode selects how file paths are rendered.
+type PathMode uint8
+
+const (
+	// PathAbsolute renders paths as reported by the runtime.
+	PathAbsolute PathMode = iota
+	// PathImport renders paths in import-path (GOPATH-relative) form, e.g.
+	// "github.com/org/mod/pkg/file.go".
+	PathImport
+	// PathModule renders paths relative to the main module, e.g.
+	// "pkg/file.go"; frames from other modules use PathImport form.
+	PathModule
+)
+
+// StackPolicy configures stack rendering. The zero value renders every frame
+// with absolute paths.
+type StackPolicy struct {
+	// DropFuncPrefixes removes frames whose fully-qualified function name
+	// starts with any prefix (e.g., "runtime.", "testing.", "net/http.").
+	DropFuncPrefixes []string
+	// PathMode rewrites file paths (see PathAbsolute, PathImport, PathModule).
+	PathMode PathMode
+	// TrimPathPrefixes strips the first matching prefix from the rendered path
+	// (e.g., "/home/ci/build/"), after PathMode is applied.
+	TrimPathPrefixes []string
+	// MaxFrames caps rendered frames after filtering; <= 0 means no cap.
+	MaxFrames int
+	// CaptureDepth caps program counters recorded at capture time; <= 0
+	// selects defaultMaxDepth. Only the package default affects capture.
+	CaptureDepth int
+}
+
+// NoisyFuncPrefixes is a convenient DropFuncPrefixes starting point.
+var NoisyFuncPrefixes = []string{"runtime.", "testing.", "net/http."}
+
+// defaultStackPolicy holds the package-level policy; nil means the zero policy.
+var defaultStackPolicy atomic.Pointer[StackPolicy]
+
+// loadStackPolicy returns the package-level policy (never nil).
+func loadStackPolicy() *StackPolicy {
+	if p := defaultStackPolicy.Load(); p != nil {
+		return p
+	}
+	return &StackPolicy{}
+}
+
+// SetDefaultStackPolicy replaces the package-level policy. Safe for
+// concurrent use; the policy's slices are copied.
+func SetDefaultStackPolicy(p StackPolicy) {
+	c := p.clone()
+	defaultStackPolicy.Store(&c)
+}
+
+// DefaultStackPolicy returns a copy of the package-level policy.
+func DefaultStackPolicy() StackPolicy {
+	return loadStackPolicy().clone()
+}
+
+// WithPolicy returns a copy of s rendered with p instead of the default.
+func (s Stack) WithPolicy(p StackPolicy) Stack {
+	c := p.clone()
+	s.pol = &c
+	return s
+}
+
+// Filtered returns a copy of the frames to render: the stack's own policy if
+// set (see WithPolicy), otherwise the package default.
+func (s Stack) Filtered() []Frame {
+	p := s.pol
+	if p == nil {
+		p = loadStackPolicy()
+	}
+	frames := s.resolved()
+	if len(frames) == 0 {
+		return nil
+	}
+	if p.isZero() {
+		out := make([]Frame, len(frames))
+		copy(out, frames)
+		return out
+	}
+	return p.apply(frames)
+}
+
+// WithStackPolicy overrides the stack policy for err's own stack.
+//   - nil → nil
+//   - native failure/defect → copy whose stack renders with p
+//   - other errors (interrupts, foreign) carry no stack → From(err)
+func WithStackPolicy(err error, p StackPolicy) Error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *failureErr:
+		n := e.clone()
+		n.stk = n.stk.WithPolicy(p)
+		return n
+	case *defectErr:
+		n := e.clone()
+		n.stk = n.stk.WithPolicy(p)
+		return n
+	}
+	return From(err)
+}
+
+func (p StackPolicy) clone() StackPolicy {
+	p.DropFuncPrefixes = append([]string(nil), p.DropFuncPrefixes...)
+	p.TrimPathPrefixes = append([]string(nil), p.TrimPathPrefixes...)
+	return p
+}
+
+// isZero reports whether p leaves frames untouched.
+func (p *StackPolicy) isZero() bool {
+	return len(p.DropFuncPrefixes) == 0 && p.PathMode == PathAbsolute &&
+		len(p.TrimPathPrefixes) == 0 && p.MaxFrames <= 0
+}
+
+// apply filters and rewrites frames into a new slice.
+func (p *StackPolicy) apply(frames []Frame) []Frame {
+	out := make([]Frame, 0, len(frames))
+	for _, fr := range frames {
+		if p.MaxFrames > 0 && len(out) >= p.MaxFrames {
+			break
+		}
+		if hasAnyPrefix(fr.Function, p.DropFuncPrefixes) {
+			continue
+		}
+		fr.File = p.rewritePath(fr)
+		out = append(out, fr)
+	}
+	return out
+}
+
+func (p *StackPolicy) rewritePath(fr Frame) string {
+	file := fr.File
+	switch p.PathMode {
+	case PathImport, PathModule:
+		if pkg := funcPackage(fr.Function); pkg != "" && file != "" {
+			file = pkg + "/" + path.Base(file)
+			if p.PathMode == PathModule {
+				if mod := mainModule(); mod != "" && strings.HasPrefix(file, mod+"/") {
+					file = strings.TrimPrefix(file, mod+"/")
+				}
+			}
+		}
+	}
+	for _, pre := range p.TrimPathPrefixes {
+		if pre != "" && strings.HasPrefix(file, pre) {
+			return strings.TrimPrefix(file, pre)
+		}
+	}
+	return file
+}
+
+// funcPackage extracts the import path from a fully-qualified function name
+// ("github.com/org/mod/pkg.(*T).M" → "github.com/org/mod/pkg").
+func funcPackage(fn string) string {
+	slash := strings.LastIndex(fn, "/")
+	dot := strings.Index(fn[slash+1:], ".")
+	if dot < 0 {
+		return ""
+	}
+	return fn[:slash+1+dot]
+}
+
+// mainModule is the main module path from build info ("" if unavailable).
+var mainModule = sync.OnceValue(func() string {
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		return bi.Main.Path
+	}
+	return ""
+})
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, pre := range prefixes {
+		if pre != "" && strings.HasPrefix(s, pre) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+// stack_policy_test.go — verification of stack filtering, trimming and path shortening.
+package xgxerror
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var policyFrames = []Frame{
+	{Function: "runtime.gopanic", File: "/usr/local/go/src/runtime/panic.go", Line: 1},
+	{Function: "github.com/acme/svc/internal/repo.(*Repo).Find", File: "/home/ci/build/svc/internal/repo/repo.go", Line: 10},
+	{Function: "github.com/acme/svc/mw.Logging.func1", File: "/home/ci/build/svc/mw/log.go", Line: 20},
+	{Function: "net/http.HandlerFunc.ServeHTTP", File: "/usr/local/go/src/net/http/server.go", Line: 30},
+	{Function: "github.com/other/lib.Do", File: "/root/go/pkg/mod/github.com/other/lib@v1.2.3/lib.go", Line: 40},
+	{Function: "main.main", File: "/home/ci/build/svc/main.go", Line: 50},
+}
+
+func TestStackPolicy_DropsByFunctionPrefix(t *testing.T) {
+	t.Parallel()
+
+	s := StackFromFrames(policyFrames).WithPolicy(StackPolicy{
+		DropFuncPrefixes: append(NoisyFuncPrefixes, "github.com/acme/svc/mw."),
+	})
+	var got []string
+	for _, fr := range s.Filtered() {
+		got = append(got, fr.Function)
+	}
+	want := "github.com/acme/svc/internal/repo.(*Repo).Find github.com/other/lib.Do main.main"
+	if strings.Join(got, " ") != want {
+		t.Fatalf("filtered functions:\n got %v\nwant %v", got, want)
+	}
+	if s.Len() != len(policyFrames) || s.Frames()[0].Function != "runtime.gopanic" {
+		t.Fatalf("Frames/Len must stay raw")
+	}
+}
+
+func TestStackPolicy_PathModesAndTrim(t *testing.T) {
+	t.Parallel()
+
+	files := func(p StackPolicy) []string {
+		var out []string
+		for _, fr := range StackFromFrames(policyFrames[1:2]).WithPolicy(p).Filtered() {
+			out = append(out, fr.File)
+		}
+		return out
+	}
+	if got := files(StackPolicy{PathMode: PathImport}); got[0] != "github.com/acme/svc/internal/repo/repo.go" {
+		t.Fatalf("PathImport: %v", got)
+	}
+	if got := files(StackPolicy{TrimPathPrefixes: []string{"/home/ci/build/"}}); got[0] != "svc/internal/repo/repo.go" {
+		t.Fatalf("TrimPathPrefixes: %v", got)
+	}
+	if got := files(StackPolicy{PathMode: PathImport, TrimPathPrefixes: []string{"github.com/acme/"}}); got[0] != "svc/internal/repo/repo.go" {
+		t.Fatalf("trim after PathImport: %v", got)
+	}
+
+	// PathModule: frames from this module become module-relative.
+	s := captureStackDefault(0).WithPolicy(StackPolicy{PathMode: PathModule, MaxFrames: 1})
+	fs := s.Filtered()
+	if len(fs) != 1 || fs[0].File != "stack_policy_test.go" {
+		t.Fatalf("PathModule/MaxFrames: %+v", fs)
+	}
+	if mainModule() == "" {
+		t.Fatalf("main module should be known in tests")
+	}
+}
+
+func TestStackPolicy_MaxFramesAppliesAfterFiltering(t *testing.T) {
+	t.Parallel()
+
+	fs := StackFromFrames(policyFrames).WithPolicy(StackPolicy{
+		DropFuncPrefixes: []string{"runtime."},
+		MaxFrames:        2,
+	}).Filtered()
+	if len(fs) != 2 || fs[0].Function != policyFrames[1].Function {
+		t.Fatalf("MaxFrames should count kept frames: %+v", fs)
+	}
+}
+
+func TestFuncPackage(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"github.com/a/b/pkg.(*T).M": "github.com/a/b/pkg",
+		"main.main":                 "main",
+		"net/http.(*conn).serve":    "net/http",
+		"nodot":                     "",
+	}
+	for in, want := range cases {
+		if got := funcPackage(in); got != want {
+			t.Fatalf("funcPackage(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWithStackPolicy_PerCallOverridesDefault(t *testing.T) {
+	t.Parallel()
+
+	e := Internal(errors.New("db"))
+	quiet := WithStackPolicy(e, StackPolicy{DropFuncPrefixes: []string{"runtime.", "testing.", "github.com/tuliorib/xgx-error."}})
+	if strings.Contains(fmt.Sprintf("%+v", quiet), "\nstack:") {
+		t.Fatalf("fully filtered stack should omit the section:\n%+v", quiet)
+	}
+	if !strings.Contains(fmt.Sprintf("%+v", e), "\nstack:") {
+		t.Fatalf("override must not affect the original error")
+	}
+	if WithStackPolicy(nil, StackPolicy{}) != nil {
+		t.Fatalf("nil should stay nil")
+	}
+	if got := WithStackPolicy(errors.New("x"), StackPolicy{}); CodeOf(got) != CodeInternal {
+		t.Fatalf("foreign errors convert via From: %v", got)
+	}
+}
+
+func TestSetDefaultStackPolicy_AppliesToRenderingAndCapture(t *testing.T) {
+	// Not parallel: mutates the package-level policy.
+	prev := DefaultStackPolicy()
+	t.Cleanup(func() { SetDefaultStackPolicy(prev) })
+
+	drop := []string{"testing."}
+	SetDefaultStackPolicy(StackPolicy{DropFuncPrefixes: drop, PathMode: PathModule})
+	drop[0] = "mutated" // the policy must hold its own copy
+
+	out := fmt.Sprintf("%+v", Internal(errors.New("db")))
+	if strings.Contains(out, "testing.tRunner") {
+		t.Fatalf("default drop prefixes not applied:\n%s", out)
+	}
+	if !strings.Contains(out, " stack_policy_test.go:") {
+		t.Fatalf("default PathModule not applied:\n%s", out)
+	}
+	if DefaultStackPolicy().DropFuncPrefixes[0] != "testing." {
+		t.Fatalf("SetDefaultStackPolicy should copy slices")
+	}
+
+	SetDefaultStackPolicy(StackPolicy{CaptureDepth: 2})
+	if n := len(captureStackDefault(0).PCs()); n == 0 || n > 2 {
+		t.Fatalf("CaptureDepth not applied: %d PCs", n)
+	}
+}
@@ -2,6 +2,10 @@
 package xgxerror
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
 	"strings"
 	"testing"
 )
@@ -29,11 +33,11 @@ func TestCaptureStack_UsesDefaultWhenMaxDepthZero(t *testing.T) {
 	t.Parallel()
 
 	s := captureStack(0, 0) // maxDepth<=0 → defaultMaxDepth
-	if len(s) == 0 {
+	if s.Empty() {
 		t.Fatalf("expected non-empty stack when maxDepth=0 (default), got 0")
 	}
-	if len(s) > defaultMaxDepth {
-		t.Fatalf("stack length exceeds defaultMaxDepth: len=%d default=%d", len(s), defaultMaxDepth)
+	if n := len(s.PCs()); n > defaultMaxDepth {
+		t.Fatalf("stack length exceeds defaultMaxDepth: len=%d default=%d", n, defaultMaxDepth)
 	}
 }
 
@@ -42,11 +46,11 @@ func TestCaptureStack_RespectsMaxDepthLimit(t *testing.T) {
 
 	const limit = 3
 	s := captureStack(0, limit)
-	if len(s) == 0 {
+	if s.Empty() {
 		t.Fatalf("expected some frames with small limit; got 0")
 	}
-	if len(s) > limit {
-		t.Fatalf("expected <= %d frames; got %d", limit, len(s))
+	if n := len(s.PCs()); n > limit {
+		t.Fatalf("expected <= %d PCs; got %d", limit, n)
 	}
 }
 
@@ -54,11 +58,11 @@ func TestCaptureStackDefault_UsesDefaultDepth(t *testing.T) {
 	t.Parallel()
 
 	s := captureStackDefault(0)
-	if len(s) == 0 {
+	if s.Empty() {
 		t.Fatalf("expected non-empty stack from captureStackDefault")
 	}
-	if len(s) > defaultMaxDepth {
-		t.Fatalf("stack length exceeds defaultMaxDepth: len=%d default=%d", len(s), defaultMaxDepth)
+	if n := len(s.PCs()); n > defaultMaxDepth {
+		t.Fatalf("stack length exceeds defaultMaxDepth: len=%d default=%d", n, defaultMaxDepth)
 	}
 }
 
@@ -66,7 +70,7 @@ func TestCaptureStack_SkipExtraSkipsCorrectFrames(t *testing.T) {
 	t.Parallel()
 
 	// skipExtra = 0 → first frame should be stackTestLevel2
-	s0 := stackTestLevel1(0)
+	s0 := stackTestLevel1(0).Frames()
 	if len(s0) == 0 {
 		t.Fatalf("got empty stack for skipExtra=0")
 	}
@@ -75,7 +79,7 @@ func TestCaptureStack_SkipExtraSkipsCorrectFrames(t *testing.T) {
 	}
 
 	// skipExtra = 1 → first frame should be stackTestLevel1
-	s1 := stackTestLevel1(1)
+	s1 := stackTestLevel1(1).Frames()
 	if len(s1) == 0 {
 		t.Fatalf("got empty stack for skipExtra=1")
 	}
@@ -84,22 +88,22 @@ func TestCaptureStack_SkipExtraSkipsCorrectFrames(t *testing.T) {
 	}
 }
 
-func TestCaptureStack_ReturnsNilWhenNoFramesCaptured(t *testing.T) {
+func TestCaptureStack_ReturnsEmptyWhenNoFramesCaptured(t *testing.T) {
 	t.Parallel()
 
 	// Use a very large skipExtra to skip beyond available frames so runtime.Callers returns 0.
-	// This should cause captureStack(...) to return nil.
+	// This should cause captureStack(...) to return the zero (empty) Stack.
 	const absurdSkip = 1 << 20
 	s := captureStack(absurdSkip, 16)
-	if s != nil {
-		t.Fatalf("expected nil stack when overly large skip filters out all frames; got len=%d", len(s))
+	if !s.Empty() || s.Frames() != nil || s.PCs() != nil {
+		t.Fatalf("expected empty stack when overly large skip filters out all frames; got len=%d", s.Len())
 	}
 }
 
 func TestStack_MetadataPresence(t *testing.T) {
 	t.Parallel()
 
-	s := stackTestLevel1(0)
+	s := stackTestLevel1(0).Frames()
 	if len(s) == 0 {
 		t.Fatalf("empty stack")
 	}
@@ -130,7 +134,7 @@ func TestBaseSkip_HidesInternalHelpers(t *testing.T) {
 	t.Parallel()
 
 	// captureStackDefault should hide runtime.Callers, captureStack, and captureStackDefault.
-	s := stackTestLevel1(0)
+	s := stackTestLevel1(0).Frames()
 	if len(s) == 0 {
 		t.Fatalf("empty stack")
 	}
@@ -145,7 +149,7 @@ func TestCapturedStack_StartsAtExpectedUserFrame(t *testing.T) {
 	t.Parallel()
 
 	// With skipExtra=0, first frame must be stackTestLevel2, i.e., the direct caller of captureStackDefault.
-	s := stackTestLevel1(0)
+	s := stackTestLevel1(0).Frames()
 	if !strings.HasSuffix(s[0].Function, "stackTestLevel2") {
 		t.Fatalf("expected first user frame to be stackTestLevel2; got %q", s[0].Function)
 	}
@@ -154,7 +158,7 @@ func TestCapturedStack_StartsAtExpectedUserFrame(t *testing.T) {
 func TestPCValuesNonZero_FilePathsNonEmpty(t *testing.T) {
 	t.Parallel()
 
-	s := captureStackDefault(0)
+	s := captureStackDefault(0).Frames()
 	if len(s) == 0 {
 		t.Fatalf("empty stack")
 	}
@@ -167,3 +171,160 @@ func TestPCValuesNonZero_FilePathsNonEmpty(t *testing.T) {
 		}
 	}
 }
+
+func TestStack_LazyResolutionIsCachedAndShared(t *testing.T) {
+	t.Parallel()
+
+	s := stackTestLevel1(0)
+	if s.st.frames != nil {
+		t.Fatalf("capture must not symbolize frames eagerly")
+	}
+	copyOf := s // copies share state
+	n := s.Len()
+	if n == 0 || copyOf.st.frames == nil {
+		t.Fatalf("Len should resolve and cache frames on the shared state")
+	}
+	fs := s.Frames()
+	fs[0].Function = "mutated"
+	if s.Frames()[0].Function == "mutated" {
+		t.Fatalf("Frames must return a defensive copy")
+	}
+	var viaAll []Frame
+	for i, fr := range s.All() {
+		if i != len(viaAll) {
+			t.Fatalf("All index out of order: %d", i)
+		}
+		viaAll = append(viaAll, fr)
+	}
+	if len(viaAll) != n || viaAll[0] != s.Frames()[0] {
+		t.Fatalf("All should yield the same frames as Frames")
+	}
+}
+
+func TestStack_ZeroValueAndStackFromFrames(t *testing.T) {
+	t.Parallel()
+
+	var zero Stack
+	if !zero.Empty() || zero.Len() != 0 || zero.Frames() != nil {
+		t.Fatalf("zero Stack should be empty")
+	}
+	for range zero.All() {
+		t.Fatalf("zero Stack should yield nothing")
+	}
+
+	in := []Frame{{Function: "pkg.A", File: "a.go", Line: 1}, {Function: "pkg.B", File: "b.go", Line: 2}}
+	s := StackFromFrames(in)
+	in[0].Function = "changed"
+	if s.Len() != 2 || s.Frames()[0].Function != "pkg.A" {
+		t.Fatalf("StackFromFrames should copy and keep frames as given: %+v", s.Frames())
+	}
+	if !StackFromFrames(nil).Empty() {
+		t.Fatalf("StackFromFrames(nil) should be empty")
+	}
+}
+
+func TestStack_ErrorsDoNotSymbolizeUntilFormatted(t *testing.T) {
+	t.Parallel()
+
+	e := Internal(errors.New("db")).(*failureErr)
+	if e.stk.Empty() || e.stk.st.frames != nil {
+		t.Fatalf("Internal should capture PCs only")
+	}
+	_ = CodeOf(e)
+	_ = e.Error()
+	if e.stk.st.frames != nil {
+		t.Fatalf("classification and Error() must not symbolize")
+	}
+	if out := fmt.Sprintf("%+v", e); !strings.Contains(out, "stack:") || e.stk.st.frames == nil {
+		t.Fatalf("%%+v should resolve frames:\n%s", out)
+	}
+}
+
+// --- Benchmarks: lazy PCs vs. the previous eager symbolization ----------------
+
+// captureStackEager is the previous implementation: resolve every frame at
+// capture time. Kept here as the benchmark reference.
+func captureStackEager(skipExtra, maxDepth int) []Frame {
+	pc := make([]uintptr, maxDepth)
+	n := runtime.Callers(2+skipExtra, pc)
+	if n == 0 {
+		return nil
+	}
+	frames := runtime.CallersFrames(pc[:n])
+	out := make([]Frame, 0, n)
+	for {
+		fr, more := frames.Next()
+		out = append(out, Frame{PC: fr.PC, File: fr.File, Line: fr.Line, Function: fr.Function})
+		if !more {
+			break
+		}
+	}
+	return out
+}
+
+var (
+	benchStack  Stack
+	benchFrames []Frame
+	benchErr    Error
+)
+
+func BenchmarkCaptureStack_Eager(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		benchFrames = captureStackEager(0, defaultMaxDepth)
+	}
+}
+
+func BenchmarkCaptureStack_Lazy(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		benchStack = captureStackDefault(0)
+	}
+}
+
+// BenchmarkCaptureStack_LazyThenResolve is the worst case: every stack is
+// eventually printed.
+func BenchmarkCaptureStack_LazyThenResolve(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		benchStack = captureStackDefault(0)
+		benchFrames = benchStack.resolved()
+	}
+}
+
+// BenchmarkInternal_CreateClassifyDiscard models the hot path the lazy stack
+// targets: Internal errors that are classified but never formatted.
+func BenchmarkInternal_CreateClassifyDiscard(b *testing.B) {
+	cause := errors.New("db")
+	b.ReportAllocs()
+	for b.Loop() {
+		benchErr = Internal(cause)
+		_ = IsRetryable(benchErr)
+	}
+}
+
+func BenchmarkInternal_CreateAndFormat(b *testing.B) {
+	cause := errors.New("db")
+	b.ReportAllocs()
+	for b.Loop() {
+		benchErr = Internal(cause)
+		_, _ = fmt.Fprintf(io.Discard, "%+v", benchErr)
+	}
+}
+
+func TestStack_CommonSuffix(t *testing.T) {
+	t.Parallel()
+
+	a := StackFromFrames([]Frame{{Function: "a", Line: 1}, {Function: "x", Line: 5}, {Function: "main", Line: 9}})
+	b := StackFromFrames([]Frame{{Function: "b", Line: 2}, {Function: "c", Line: 3}, {Function: "x", Line: 5}, {Function: "main", Line: 9}})
+	if got := a.CommonSuffix(b); got != 2 {
+		t.Fatalf("CommonSuffix: want 2 got %d", got)
+	}
+	c := StackFromFrames([]Frame{{Function: "x", Line: 6}, {Function: "main", Line: 9}})
+	if got := a.CommonSuffix(c); got != 1 {
+		t.Fatalf("different call lines must not match: got %d", got)
+	}
+	if a.CommonSuffix(Stack{}) != 0 || (Stack{}).CommonSuffix(a) != 0 {
+		t.Fatalf("empty stacks share nothing")
+	}
+}
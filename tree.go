@@ -0,0 +1,145 @@
+// tree.go — ASCII tree rendering of error graphs.
+//
+// %+v on a Join prints each child's verbose form one after another, which
+// flattens the causal structure. Tree (and %#v on errors from this package)
+// draws the unwrap graph instead, one node per line:
+//
+//	*xgxerror.multi code=internal (2 errors)
+//	├─ *xgxerror.failureErr code=not_found msg="user not found" fields=2
+//	└─ *xgxerror.failureErr code=internal msg="load orders" fields=1
+//	   └─ *fmt.wrapError msg="query"
+//	      └─ *errors.errorString msg="connection reset"
+//
+// Semantics:
+//   - Each line shows the node's dynamic type, code (if any), raw message and
+//     own field count; Joins show their child count instead of a message.
+//   - A foreign wrapper's message is shown without its cause's text (the
+//     "query: connection reset" of fmt.Errorf becomes "query").
+//   - Unlike Walk, repeated nodes are not skipped silently: a node already
+//     drawn elsewhere is printed once more marked "(shared)", and a node that
+//     is its own ancestor is marked "(cycle)"; neither is expanded again.
+//   - Field values are never printed, so secrets cannot leak here.
+package xgxerror
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tree renders err's unwrap graph as an indented tree ("" for nil).
+func Tree(err error) string {
+	if err == nil {
+		return ""
+	}
+	t := treeWriter{
+		onPath: make(map[any]struct{}, 8),
+		drawn:  make(map[any]struct{}, 8),
+	}
+	t.node(err, "", "", 0)
+	return strings.TrimSuffix(t.b.String(), "\n")
+}
+
+type treeWriter struct {
+	b      strings.Builder
+	onPath map[any]struct{} // ancestors of the node being drawn
+	drawn  map[any]struct{} // every node expanded so far
+}
+
+// node writes e's line after lead and its children indented by indent.
+func (t *treeWriter) node(e error, lead, indent string, depth int) {
+	t.b.WriteString(lead)
+	t.b.WriteString(treeLabel(e))
+
+	key, tracked := treeKey(e)
+	if tracked {
+		if _, ok := t.onPath[key]; ok {
+			t.b.WriteString(" (cycle)\n")
+			return
+		}
+		if _, ok := t.drawn[key]; ok {
+			t.b.WriteString(" (shared)\n")
+			return
+		}
+		t.onPath[key] = struct{}{}
+		t.drawn[key] = struct{}{}
+		defer delete(t.onPath, key)
+	}
+	t.b.WriteByte('\n')
+
+	const maxDepth = 1 << 12 // generous cap against runaway graphs
+	if depth >= maxDepth {
+		return
+	}
+	kids := treeChildren(e)
+	for i, c := range kids {
+		if i == len(kids)-1 {
+			t.node(c, indent+"└─ ", indent+"   ", depth+1)
+		} else {
+			t.node(c, indent+"├─ ", indent+"│  ", depth+1)
+		}
+	}
+}
+
+// treeKey returns e's identity with the rules of markSeen: the value itself
+// if comparable, else its pointer; other nodes are untracked.
+func treeKey(e error) (any, bool) {
+	if isComparable(e) {
+		return e, true
+	}
+	if id, ok := ptrID(e); ok {
+		return id, true
+	}
+	return nil, false
+}
+
+// treeChildren returns e's non-nil children (multi-unwrap first, like Walk).
+func treeChildren(e error) []error {
+	if m, ok := e.(multiUnwrapper); ok {
+		var out []error
+		for _, c := range m.Unwrap() {
+			if c != nil {
+				out = append(out, c)
+			}
+		}
+		return out
+	}
+	if s, ok := e.(singleUnwrapper); ok {
+		if u := s.Unwrap(); u != nil {
+			return []error{u}
+		}
+	}
+	return nil
+}
+
+// treeLabel renders one node: type, code, message (or child count) and field count.
+func treeLabel(e error) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%T", e)
+	if c, ok := e.(coder); ok && c.CodeVal() != "" {
+		b.WriteString(" code=")
+		b.WriteString(string(c.CodeVal()))
+	}
+
+	if m, ok := e.(multiUnwrapper); ok {
+		fmt.Fprintf(&b, " (%d errors)", len(m.Unwrap()))
+		return b.String()
+	}
+
+	n := Inspect(e)
+	msg := n.Msg
+	if n.Kind == KindForeign && n.Cause != nil {
+		msg = strings.TrimSuffix(msg, ": "+n.Cause.Error())
+	}
+	if msg != "" {
+		fmt.Fprintf(&b, " msg=%q", msg)
+	}
+
+	fieldCount := len(n.Fields)
+	if xe, ok := e.(Error); ok && n.Kind == KindForeign {
+		fieldCount = len(xe.Context())
+	}
+	if fieldCount > 0 {
+		fmt.Fprintf(&b, " fields=%d", fieldCount)
+	}
+	return b.String()
+}
@@ -0,0 +1,88 @@
+// tree_test.go — verification of ASCII tree rendering.
+package xgxerror
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// cyclicErr unwraps to whatever next points at, so tests can build cycles.
+type cyclicErr struct {
+	msg  string
+	next error
+}
+
+func (e *cyclicErr) Error() string { return e.msg }
+func (e *cyclicErr) Unwrap() error { return e.next }
+
+func TestTree_JoinOfChains(t *testing.T) {
+	t.Parallel()
+
+	err := Join(
+		NotFound("user", 1),
+		Wrap(fmt.Errorf("query: %w", errors.New("connection reset")), "load orders", "table", "orders"),
+	)
+	want := strings.Join([]string{
+		`*xgxerror.multi code=internal (2 errors)`,
+		`├─ *xgxerror.failureErr code=not_found msg="user not found" fields=2`,
+		`└─ *xgxerror.failureErr code=internal msg="load orders" fields=1`,
+		`   └─ *fmt.wrapError msg="query"`,
+		`      └─ *errors.errorString msg="connection reset"`,
+	}, "\n")
+	if got := Tree(err); got != want {
+		t.Fatalf("Tree:\n%s\nwant:\n%s", got, want)
+	}
+	if got := fmt.Sprintf("%#v", err); got != want {
+		t.Fatalf("%%#v on join:\n%s", got)
+	}
+	if Tree(nil) != "" {
+		t.Fatalf("nil should render empty")
+	}
+}
+
+func TestTree_SharedAndCycleMarked(t *testing.T) {
+	t.Parallel()
+
+	shared := errors.New("shared")
+	err := Join(fmt.Errorf("a: %w", shared), shared)
+	got := Tree(err)
+	if !strings.HasSuffix(got, `└─ *errors.errorString msg="shared" (shared)`) ||
+		strings.Count(got, "shared") != 3 {
+		t.Fatalf("shared node should be marked:\n%s", got)
+	}
+
+	a := &cyclicErr{msg: "a"}
+	b := &cyclicErr{msg: "b", next: a}
+	a.next = b
+	want := strings.Join([]string{
+		`*xgxerror.cyclicErr msg="a"`,
+		`└─ *xgxerror.cyclicErr msg="b"`,
+		`   └─ *xgxerror.cyclicErr msg="a" (cycle)`,
+	}, "\n")
+	if got := Tree(a); got != want {
+		t.Fatalf("cycle:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTree_SharpVerbOnNativeTypes(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		err  error
+		want string
+	}{
+		{Wrap(Defect(errors.New("bug")), "x"), "*xgxerror.defectErr code=defect msg=\"x\"\n└─ *errors.errorString msg=\"bug\""},
+		{Interrupt("stop"), "*xgxerror.interruptErr code=interrupt msg=\"stop\"\n└─ *errors.errorString msg=\"context canceled\""},
+		{BadRequest("bad"), `*xgxerror.failureErr code=bad_request msg="bad"`},
+	}
+	for _, tc := range cases {
+		if got := fmt.Sprintf("%#v", tc.err); got != tc.want {
+			t.Fatalf("%%#v:\n%s\nwant:\n%s", got, tc.want)
+		}
+		if strings.Contains(fmt.Sprintf("%+v", tc.err), "└─") {
+			t.Fatalf("%%+v must keep the verbose format")
+		}
+	}
+}
@@ -39,6 +39,21 @@
 //	    return err
 //	}
 //
+// Sensitive fields
+//
+//	Declare a field with FieldSensitive to wrap every Set value in Secret, so
+//	%+v, Context() and exporters render it as RedactedText. Get/MustGet are
+//	privileged typed accessors and return the raw value.
+//
+//	var FEmail = xgxerror.FieldOf[string]("email", xgxerror.FieldSensitive)
+//
+// Pinned fields
+//
+//	Declare a field with FieldPinned for must-keep identifiers: every Set value
+//	is pinned, so CtxBound evicts other fields first and never drops it.
+//
+//	var FRequestID = xgxerror.FieldOf[string]("request_id", xgxerror.FieldPinned)
+//
 // Caveats
 //   - TypedField relies on Go’s type assertions. The dynamic type stored in the
 //     error’s context MUST match T exactly; no implicit conversions are made.
@@ -55,34 +70,63 @@ type fieldLookup interface {
 	lookupFieldLast(key string) (any, bool)
 }
 
+// FieldOption adjusts how a TypedField stores its values. Options are bit
+// flags and may be combined with |.
+type FieldOption uint8
+
+const (
+	// FieldSensitive wraps values in Secret on Set so renderers redact them.
+	FieldSensitive FieldOption = 1 << iota
+	// FieldPinned pins values on Set so CtxBound never evicts them.
+	FieldPinned
+)
+
 // TypedField is a small, zero-policy helper for type-safe context access.
 // T is the Go type you intend to store/retrieve for the given key.
 type TypedField[T any] struct {
-	key string
+	key  string
+	opts FieldOption
 }
 
-// FieldOf constructs a TypedField[T] for a given key.
+// FieldOf constructs a TypedField[T] for a given key and optional options.
 // Keys SHOULD be snake_case for consistency across logs/exports.
 //
 // Note: Named FieldOf to avoid collision with the package's Field struct in context.go.
-func FieldOf[T any](key string) TypedField[T] {
-	return TypedField[T]{key: key}
+func FieldOf[T any](key string, opts ...FieldOption) TypedField[T] {
+	f := TypedField[T]{key: key}
+	for _, o := range opts {
+		f.opts |= o
+	}
+	return f
 }
 
 // Key returns the underlying string key for this field.
 func (f TypedField[T]) Key() string { return f.key }
 
+// Sensitive reports whether the field was declared with FieldSensitive.
+func (f TypedField[T]) Sensitive() bool { return f.opts&FieldSensitive != 0 }
+
+// Pinned reports whether the field was declared with FieldPinned.
+func (f TypedField[T]) Pinned() bool { return f.opts&FieldPinned != 0 }
+
 // Set attaches (key = val) to e and returns a NEW Error.
 // If e is nil, Set behaves like With(nil, key, val): it creates a NEW internal
 // failure carrying the field. If you do not want that, pass a non-nil Error.
 func (f TypedField[T]) Set(e Error, val T) Error {
-	// Route through public adapter to preserve nil behavior & semantics.
-	return With(e, f.key, any(val))
+	var v any = val
+	if f.Sensitive() {
+		v = Secret(val)
+	}
+	// Route through public adapters to preserve nil behavior & semantics.
+	if f.Pinned() {
+		return retrace(WithPinned(e, f.key, v), 0)
+	}
+	return retrace(With(e, f.key, v), 0)
 }
 
 // Get retrieves the typed value for this field from e.
 // Returns (zero, false) if e is nil, the field is absent, or the value has a
-// different dynamic type than T.
+// different dynamic type than T. Secret-wrapped values are unwrapped.
 //
 // Fast path: if e is a native xgx error, use fieldLookup (zero allocs, last-write-wins).
 // Fallback: if not native, use e.Context() (allocates a map copy).
@@ -98,7 +142,7 @@ func (f TypedField[T]) Get(e Error) (T, bool) {
 		if !hit {
 			return zero, false
 		}
-		tv, ok := val.(T)
+		tv, ok := unwrapSecret(val).(T)
 		if !ok {
 			return zero, false
 		}
@@ -138,7 +182,7 @@ func (f TypedField[T]) MustGet(e Error) T {
 		if !hit {
 			panic(fmt.Errorf("xgxerror.TypedField[%T](%q): field missing", zero, f.key))
 		}
-		tv, ok := val.(T)
+		tv, ok := unwrapSecret(val).(T)
 		if !ok {
 			panic(fmt.Errorf("xgxerror.TypedField[%T](%q): wrong dynamic type (%T)", zero, f.key, val))
 		}
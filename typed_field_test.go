@@ -311,3 +311,27 @@ func TestMustGet_UsesIteratorForNativeErrors(t *testing.T) {
 		t.Fatalf("MustGet fast path allocs=%v, want 0", allocs)
 	}
 }
+
+func TestSet_PinnedFieldSurvivesCtxBound(t *testing.T) {
+	t.Parallel()
+
+	fReq := FieldOf[string]("request_id", FieldPinned)
+	if !fReq.Pinned() || FieldOf[string]("x").Pinned() {
+		t.Fatalf("Pinned() should reflect FieldPinned")
+	}
+	fSecret := FieldOf[string]("session", FieldPinned|FieldSensitive)
+
+	e := fSecret.Set(fReq.Set(NotFound("user", 1), "r1"), "s3cr3t")
+	for i := 0; i < 3; i++ {
+		e = e.CtxBound("", 2, "attempt", i)
+	}
+	if got, ok := fReq.Get(e); !ok || got != "r1" {
+		t.Fatalf("pinned typed field evicted: %v %v", got, ok)
+	}
+	if got, ok := fSecret.Get(e); !ok || got != "s3cr3t" || e.Context()["session"] != RedactedText {
+		t.Fatalf("pinned sensitive field: %v %v %v", got, ok, e.Context())
+	}
+	if _, ok := e.Context()["entity"]; ok {
+		t.Fatalf("unpinned fields should be evicted first: %v", e.Context())
+	}
+}
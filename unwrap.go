@@ -20,6 +20,8 @@
 //   - Flatten:     collects LEAVES only (nodes with no children) in DFS order.
 //   - Root:        first DFS leaf (deepest along the first path), nil-safe.
 //   - Has:         nil-safe wrapper over errors.Is.
+//   - Lazy, range-over-func forms (All, Leaves, AllWithPath) live in iter.go;
+//     positional traversal (WalkPath, Locate) in path.go.
 //
 // Performance:
 //   - Reflection is used minimally to decide comparability/pointer identity; this is not
@@ -114,6 +116,17 @@ func Flatten(err error) []error {
 		return []error{err}
 	}
 
+	out := make([]error, 0, 4)
+	walkLeaves(err, func(e error) bool {
+		out = append(out, e)
+		return true
+	})
+	return out
+}
+
+// walkLeaves yields leaf errors (nodes with no children) in depth-first order;
+// it is the lazy core of Flatten.
+func walkLeaves(err error, yield func(error) bool) {
 	const maxDepth = 1 << 12 // generous cap against runaway graphs
 
 	type frame struct {
@@ -121,7 +134,6 @@ func Flatten(err error) []error {
 		idx int // next child index to visit (for multi)
 	}
 
-	out := make([]error, 0, 4)
 	stack := make([]frame, 0, 8)
 	seenErr := make(map[error]struct{}, 16)
 	seenPtr := make(map[uintptr]struct{}, 16)
@@ -166,12 +178,12 @@ func Flatten(err error) []error {
 			}
 		}
 
-		// Leaf node: record and pop.
-		out = append(out, top.e)
+		// Leaf node: yield and pop.
+		if !yield(top.e) {
+			return
+		}
 		stack = stack[:len(stack)-1]
 	}
-
-	return out
 }
 
 // Walk traverses an error graph depth-first and calls visit for each DISTINCT
@@ -0,0 +1,161 @@
+// validation.go — field-path-aware validation builder.
+//
+// A Validation collects Violations (field path, rule name, params) and turns
+// them into ONE CodeInvalid error, instead of a Join of Invalid(field, reason)
+// errors whose nesting is lost and whose CodeOf depends on child order.
+//
+// Example:
+//
+//	v := xgxerror.NewValidation()
+//	if u.Email == "" {
+//	    v.Field("email").Invalid("required")
+//	}
+//	for i, it := range u.Items {
+//	    if it.Qty > 10 {
+//	        v.Field("items").Index(i).Field("qty").Invalid("max", "limit", 10)
+//	    }
+//	}
+//	return v.Err() // nil when there are no violations
+//
+// Semantics:
+//   - Paths are dotted with bracketed indexes ("items[2].qty"); Field accepts
+//     a dotted segment as-is ("address.zip").
+//   - Violations keep insertion order and are attached to the error as a
+//     Violations detail: ViolationsOf (or DetailOf[Violations]) reads them
+//     back, %+v renders them under "details:", and exporters carry them.
+//   - A Validation is not safe for concurrent use.
+package xgxerror
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Violation is one failed rule on one field.
+type Violation struct {
+	Field  string         `json:"field"`            // path, e.g. "items[2].qty"; "" for the whole value
+	Rule   string         `json:"rule"`             // rule name, e.g. "required", "max"
+	Params map[string]any `json:"params,omitempty"` // rule parameters, e.g. {"limit": 10}
+}
+
+// String renders v as "field: rule(k=v, ...)"; params are sorted by key.
+func (v Violation) String() string {
+	var b strings.Builder
+	if v.Field != "" {
+		b.WriteString(v.Field)
+		b.WriteString(": ")
+	}
+	b.WriteString(v.Rule)
+	if len(v.Params) > 0 {
+		keys := make([]string, 0, len(v.Params))
+		for k := range v.Params {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteByte('(')
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "%s=%v", k, v.Params[k])
+		}
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
+// Violations is the typed list attached to errors built by Validation.Err.
+type Violations []Violation
+
+// String joins the violations with "; ".
+func (vs Violations) String() string {
+	parts := make([]string, len(vs))
+	for i, v := range vs {
+		parts[i] = v.String()
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Format renders the String form for every verb, so %+v stays on one line.
+func (vs Violations) Format(f fmt.State, _ rune) { _, _ = fmt.Fprint(f, vs.String()) }
+
+// ViolationsOf returns every violation in err's graph (see DetailsOf), or nil.
+func ViolationsOf(err error) []Violation {
+	var out []Violation
+	for _, vs := range DetailsOf[Violations](err) {
+		out = append(out, vs...)
+	}
+	return out
+}
+
+// Validation collects violations. The zero value is ready to use.
+type Validation struct {
+	violations Violations
+}
+
+// NewValidation returns an empty Validation.
+func NewValidation() *Validation { return &Validation{} }
+
+// Field starts a path at name (which may itself be dotted, e.g. "address.zip").
+func (v *Validation) Field(name string) FieldRef { return FieldRef{v: v, path: name} }
+
+// Len reports how many violations have been recorded.
+func (v *Validation) Len() int { return len(v.violations) }
+
+// Violations returns a copy of the recorded violations in insertion order.
+func (v *Validation) Violations() []Violation {
+	return append([]Violation(nil), v.violations...)
+}
+
+// Err returns nil if nothing was recorded; otherwise a CodeInvalid failure
+// carrying a copy of the violations as a Violations detail.
+func (v *Validation) Err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &failureErr{
+		msg:     "validation failed",
+		code:    CodeInvalid,
+		ctx:     emptyFields,
+		details: []any{Violations(v.Violations())},
+	}
+}
+
+// FieldRef addresses one field path inside a Validation.
+type FieldRef struct {
+	v    *Validation
+	path string
+}
+
+// Field descends into a named child ("address" → "address.zip").
+func (r FieldRef) Field(name string) FieldRef {
+	if r.path == "" {
+		return FieldRef{v: r.v, path: name}
+	}
+	return FieldRef{v: r.v, path: r.path + "." + name}
+}
+
+// Index descends into an array element ("items" → "items[2]").
+func (r FieldRef) Index(i int) FieldRef {
+	return FieldRef{v: r.v, path: r.path + "[" + strconv.Itoa(i) + "]"}
+}
+
+// Path returns the rendered path ("items[2].qty").
+func (r FieldRef) Path() string { return r.path }
+
+// Invalid records a violation of rule at this path. params are key/value
+// pairs with the same rules as Ctx (non-string keys drop their pair; a
+// trailing key gets nil). It returns r so several rules can be chained.
+func (r FieldRef) Invalid(rule string, params ...any) FieldRef {
+	viol := Violation{Field: r.path, Rule: rule}
+	if fs := ctxFromKV(params...); len(fs) > 0 {
+		viol.Params = make(map[string]any, len(fs))
+		for _, f := range fs {
+			viol.Params[f.Key] = f.Val
+		}
+	}
+	r.v.violations = append(r.v.violations, viol)
+	return r
+}
@@ -0,0 +1,89 @@
+// validation_test.go — verification of the validation builder.
+package xgxerror
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestValidation_NoViolationsIsNil(t *testing.T) {
+	t.Parallel()
+
+	if err := NewValidation().Err(); err != nil {
+		t.Fatalf("want nil, got %v", err)
+	}
+	var v Validation // zero value is usable
+	v.Field("x")     // addressing a field records nothing
+	if v.Len() != 0 || v.Err() != nil {
+		t.Fatalf("zero Validation should stay empty")
+	}
+}
+
+func TestValidation_PathsRulesAndParams(t *testing.T) {
+	t.Parallel()
+
+	v := NewValidation()
+	v.Field("address.zip").Invalid("required")
+	v.Field("items").Index(2).Field("qty").Invalid("max", "limit", 10).Invalid("even")
+	v.Field("").Field("name").Invalid("length", "min", 1, "max", 64)
+	v.Field("").Invalid("one_of", "fields", "email|phone")
+
+	err := v.Err()
+	if CodeOf(err) != CodeInvalid || !HasCode(err, CodeInvalid) {
+		t.Fatalf("want a single invalid error, got %v", err)
+	}
+	vs := ViolationsOf(err)
+	var got []string
+	for _, x := range vs {
+		got = append(got, x.String())
+	}
+	want := "address.zip: required|items[2].qty: max(limit=10)|items[2].qty: even|name: length(max=64, min=1)|one_of(fields=email|phone)"
+	if strings.Join(got, "|") != want {
+		t.Fatalf("violations:\n got %s\nwant %s", strings.Join(got, "|"), want)
+	}
+	if vs[1].Params["limit"] != 10 {
+		t.Fatalf("params keep their Go type: %#v", vs[1].Params)
+	}
+	if v.Field("items").Index(0).Path() != "items[0]" {
+		t.Fatalf("Path rendering")
+	}
+}
+
+func TestValidation_ErrIsASnapshot(t *testing.T) {
+	t.Parallel()
+
+	v := NewValidation()
+	v.Field("a").Invalid("required")
+	err := v.Err()
+	v.Field("b").Invalid("required")
+	if len(ViolationsOf(err)) != 1 || v.Len() != 2 || len(ViolationsOf(v.Err())) != 2 {
+		t.Fatalf("later violations must not leak into earlier errors")
+	}
+	got := v.Violations()
+	got[0].Rule = "mutated"
+	if v.Violations()[0].Rule != "required" {
+		t.Fatalf("Violations should return a copy")
+	}
+}
+
+func TestValidation_RenderedAndJoinable(t *testing.T) {
+	t.Parallel()
+
+	v := NewValidation()
+	v.Field("email").Invalid("required")
+	v.Field("age").Invalid("min", "value", 0)
+	err := v.Err()
+
+	out := fmt.Sprintf("%+v", err)
+	if !strings.Contains(out, `code=invalid msg="validation failed"`) ||
+		!strings.Contains(out, "\n  xgxerror.Violations email: required; age: min(value=0)") {
+		t.Fatalf("%%+v rendering:\n%s", out)
+	}
+
+	other := NewValidation()
+	other.Field("name").Invalid("required")
+	if vs := ViolationsOf(Join(err, Wrap(other.Err(), "profile"))); len(vs) != 3 {
+		t.Fatalf("ViolationsOf should gather across the graph: %v", vs)
+	}
+}
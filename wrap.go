@@ -15,7 +15,13 @@
 //         because the caller is asserting error-worthy context (not just converting).
 //       • If err already implements xgxerror.Error → augmented immutably.
 //       • Otherwise → wrapped as an internal failure with provided context.
+//   - Adopt(err, kv...):
+//       • Adds context only. nil → nil; a foreign error is wrapped in a tag
+//         with neither message nor code, so its text and classification show
+//         through unchanged.
 //   - This asymmetry (From(nil) == nil, Wrap(nil, ...) != nil) is intentional and documented.
+//   - With return traces on (SetReturnTraces), every helper except From records
+//     ITS caller, not the fluent method it delegates to (see returntrace.go).
 package xgxerror
 
 // From converts any error into Error. If err is nil, From returns nil (pure conversion).
@@ -47,35 +53,95 @@ func From(err error) Error {
 func Wrap(err error, msg string, kv ...any) Error {
 	if err == nil {
 		// Create a failure with context only (internal by default).
-		return &failureErr{msg: msg, code: CodeInternal, ctx: ctxFromKV(kv...)}
+		return &failureErr{msg: msg, code: CodeInternal, ctx: ctxFromKV(kv...), trace: appendTrace(nil, 0)}
 	}
 	if xe, ok := err.(Error); ok {
-		return xe.Ctx(msg, kv...)
+		return retrace(xe.Ctx(msg, kv...), 0)
 	}
 	return &failureErr{
 		msg:   msg,
 		code:  CodeInternal,
 		ctx:   ctxFromKV(kv...),
 		cause: err,
+		trace: appendTrace(nil, 0),
 	}
 }
 
+// Adopt attaches context to err without describing or classifying it.
+//   - nil → nil
+//   - xgxerror.Error → augmented immutably (message and code untouched)
+//   - other error → wrapped in a TAG: a failure with fields but no message
+//     and no code. Error() is the cause's text, and CodeOf, HasCode,
+//     DominantCode and IsInterrupt see the graph below unchanged (a wrapped
+//     context.Canceled is still an interrupt, a wrapped Join keeps all of
+//     its branches).
+// Use it where a layer tags errors passing through (attempt numbers, task
+// ids) rather than describing them.
+func Adopt(err error, kv ...any) Error {
+	if err == nil {
+		return nil
+	}
+	if xe, ok := err.(Error); ok {
+		return retrace(xe.Ctx("", kv...), 0)
+	}
+	return &failureErr{ctx: ctxFromKV(kv...), cause: err, tag: true, trace: appendTrace(nil, 0)}
+}
+
+// isTag reports whether e is a bare tag from Adopt: still without a message
+// or code of its own, so it renders and classifies as its cause.
+func (e *failureErr) isTag() bool {
+	return e.tag && e.msg == "" && e.code == "" && e.cause != nil
+}
+
 // With attaches a single key/value to any error immutably.
 //   - nil → creates new internal failure with that key/value.
 //   - xgxerror.Error → augments immutably.
 //   - other → wraps as internal failure and adds key/value.
 func With(err error, key string, val any) Error {
 	if err == nil {
-		return &failureErr{msg: "error", code: CodeInternal, ctx: ctxFromKV(key, val)}
+		return &failureErr{msg: "error", code: CodeInternal, ctx: ctxFromKV(key, val), trace: appendTrace(nil, 0)}
 	}
 	if xe, ok := err.(Error); ok {
-		return xe.With(key, val)
+		return retrace(xe.With(key, val), 0)
 	}
 	return &failureErr{
 		msg:   "internal error",
 		code:  CodeInternal,
 		ctx:   ctxFromKV(key, val),
 		cause: err,
+		trace: appendTrace(nil, 0),
+	}
+}
+
+// WithPinned is With for a field CtxBound never evicts (request ids, tenants,
+// ...). nil and foreign errors are wrapped as With does, with the field
+// pinned; a third-party Error implementation gets a plain With.
+func WithPinned(err error, key string, val any) Error {
+	f := ctxField{Field: Field{Key: key, Val: val}, pinned: true}
+	switch e := err.(type) {
+	case nil:
+		return &failureErr{msg: "error", code: CodeInternal, ctx: ctxCloneAppend(emptyFields, f), trace: appendTrace(nil, 0)}
+	case *failureErr:
+		n := e.cloneAt(0)
+		n.ctx = ctxCloneAppend(n.ctx, f)
+		return n
+	case *defectErr:
+		n := e.cloneAt(0)
+		n.ctx = ctxCloneAppend(n.ctx, f)
+		return n
+	case *interruptErr:
+		n := e.cloneAt(0)
+		n.ctx = ctxCloneAppend(n.ctx, f)
+		return n
+	case Error:
+		return retrace(e.With(key, val), 0)
+	}
+	return &failureErr{
+		msg:   "internal error",
+		code:  CodeInternal,
+		ctx:   ctxCloneAppend(emptyFields, f),
+		cause: err,
+		trace: appendTrace(nil, 0),
 	}
 }
 
@@ -85,23 +151,24 @@ func With(err error, key string, val any) Error {
 //   - other → wraps as internal failure and applies code.
 func Recode(err error, c Code) Error {
 	if err == nil {
-		return &failureErr{msg: "error", code: c, ctx: emptyFields}
+		return &failureErr{msg: "error", code: c, ctx: emptyFields, trace: appendTrace(nil, 0)}
 	}
 	if xe, ok := err.(Error); ok {
-		return xe.Code(c)
+		return retrace(xe.Code(c), 0)
 	}
 	return &failureErr{
 		msg:   "internal error",
 		code:  c,
 		ctx:   emptyFields,
 		cause: err,
+		trace: appendTrace(nil, 0),
 	}
 }
 
 // WithStack attaches a stack trace to any error immutably.
 // For non-xgx errors, it wraps as internal and captures the stack.
 func WithStack(err error) Error {
-	return WithStackSkip(err, 0)
+	return retrace(WithStackSkip(err, 0), 0)
 }
 
 // WithStackSkip attaches a stack while skipping 'skip' frames beyond this call.
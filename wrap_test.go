@@ -1,8 +1,10 @@
-// wrap_test.go — verification of adapter helpers: From / Wrap / With / Recode / WithStack(*).
+// wrap_test.go — verification of adapter helpers: From / Wrap / Adopt / With / Recode / WithStack(*).
 package xgxerror
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -54,8 +56,8 @@ func TestFrom_PlainWrapsInternal_NoStack(t *testing.T) {
 	if !errors.Is(f, plain) {
 		t.Fatalf("From(plain) should unwrap to plain")
 	}
-	if len(f.stk) != 0 {
-		t.Fatalf("From(plain) should not capture stack (opt-in); got %d frames", len(f.stk))
+	if !f.stk.Empty() {
+		t.Fatalf("From(plain) should not capture stack (opt-in); got %d frames", f.stk.Len())
 	}
 }
 
@@ -106,8 +108,50 @@ func TestWrap_PlainWrapsInternalWithContext(t *testing.T) {
 		t.Fatalf("missing ctx attempt=3; got %v", f.Context())
 	}
 	// no stack unless WithStack*
-	if len(f.stk) != 0 {
-		t.Fatalf("Wrap(plain) should not capture stack; got %d frames", len(f.stk))
+	if !f.stk.Empty() {
+		t.Fatalf("Wrap(plain) should not capture stack; got %d frames", f.stk.Len())
+	}
+}
+
+// ---- tests: Adopt -------------------------------------------------------------
+
+func TestAdopt_TagsWithoutClassifying(t *testing.T) {
+	t.Parallel()
+	if Adopt(nil, "k", 1) != nil {
+		t.Fatalf("Adopt(nil) must be nil")
+	}
+
+	cause := fmt.Errorf("repo: %w", Conflict("dup"))
+	got := Adopt(cause, "task", 2)
+	if got.Error() != cause.Error() || CodeOf(got) != CodeConflict || got.CodeVal() != "" {
+		t.Fatalf("foreign: %q code=%s own=%q", got, CodeOf(got), got.CodeVal())
+	}
+	if !errors.Is(got, cause) || got.Context()["task"] != 2 {
+		t.Fatalf("foreign: cause or field lost: %v", got.Context())
+	}
+	if plain := Adopt(errors.New("io")); plain.Error() != "io" || CodeOf(plain) != "" || HasCode(plain, CodeInternal) {
+		t.Fatalf("plain: %q code=%s", plain, CodeOf(plain))
+	}
+
+	canceled := Adopt(fmt.Errorf("query: %w", context.Canceled), "task", 1)
+	if !IsInterrupt(canceled) || DominantCode(canceled, nil) != CodeInterrupt || HasCode(canceled, CodeInternal) {
+		t.Fatalf("canceled: interrupt=%v dominant=%q", IsInterrupt(canceled), DominantCode(canceled, nil))
+	}
+
+	joined := Adopt(Join(Invalid("email", "format"), Internal(errors.New("db"))), "task", 1)
+	if DominantCode(joined, nil) != CodeInternal || CodeOf(joined) != CodeInvalid || !HasCode(joined, CodeInternal) {
+		t.Fatalf("joined: dominant=%q first=%q", DominantCode(joined, nil), CodeOf(joined))
+	}
+
+	native := Adopt(NotFound("user", 1), "task", 3)
+	if native.Error() != "not_found: user not found" || native.Context()["task"] != 3 {
+		t.Fatalf("native: %q %v", native, native.Context())
+	}
+	if got := Recode(Adopt(errors.New("io")), CodeUnavailable); got.Error() != "unavailable" {
+		t.Fatalf("a recoded tag renders like any failure: %q", got)
+	}
+	if got := Wrap(errors.New("io"), ""); got.Error() != "internal" {
+		t.Fatalf("Wrap with an empty message is unchanged: %q", got)
 	}
 }
 
@@ -188,7 +232,7 @@ func TestWithStack_NilCreatesWithStack(t *testing.T) {
 	t.Parallel()
 	got := WithStack(nil)
 	f := asFailure(t, got)
-	if len(f.stk) == 0 {
+	if f.stk.Empty() {
 		t.Fatalf("WithStack(nil) must capture stack")
 	}
 }
@@ -198,11 +242,11 @@ func TestWithStack_XgxDelegates(t *testing.T) {
 	base := BadRequest("x")
 	got := WithStack(base)
 	f := asFailure(t, got)
-	if len(f.stk) == 0 {
+	if f.stk.Empty() {
 		t.Fatalf("WithStack(xgx) must capture stack")
 	}
 	// original unchanged
-	if bf := asFailure(t, base); len(bf.stk) != 0 {
+	if bf := asFailure(t, base); !bf.stk.Empty() {
 		t.Fatalf("original must remain without stack")
 	}
 }
@@ -215,7 +259,7 @@ func TestWithStack_PlainWrapsWithStack(t *testing.T) {
 	if f.code != CodeInternal || !errors.Is(f, plain) {
 		t.Fatalf("WithStack(plain) mismatch: code=%s unwrap=%v", f.code, errors.Is(f, plain))
 	}
-	if len(f.stk) == 0 {
+	if f.stk.Empty() {
 		t.Fatalf("WithStack(plain) should capture stack")
 	}
 }
@@ -229,20 +273,97 @@ func TestWithStackSkip_AddsCorrectSkipOffset(t *testing.T) {
 	// skip=0 → first frame should be wsLevel2 (direct caller of WithStackSkip).
 	e0 := wsLevel1(0, base)
 	f0 := asFailure(t, e0)
-	if len(f0.stk) == 0 {
+	if f0.stk.Empty() {
 		t.Fatalf("WithStackSkip(skip=0) did not capture stack")
 	}
-	if !strings.HasSuffix(f0.stk[0].Function, "wsLevel2") {
-		t.Fatalf("skip=0: expected first frame wsLevel2; got %q", f0.stk[0].Function)
+	if !strings.HasSuffix(f0.stk.Frames()[0].Function, "wsLevel2") {
+		t.Fatalf("skip=0: expected first frame wsLevel2; got %q", f0.stk.Frames()[0].Function)
 	}
 
 	// skip=1 → also skip wsLevel2; now first frame should be wsLevel1.
 	e1 := wsLevel1(1, base)
 	f1 := asFailure(t, e1)
-	if len(f1.stk) == 0 {
+	if f1.stk.Empty() {
 		t.Fatalf("WithStackSkip(skip=1) did not capture stack")
 	}
-	if !strings.HasSuffix(f1.stk[0].Function, "wsLevel1") {
-		t.Fatalf("skip=1: expected first frame wsLevel1; got %q", f1.stk[0].Function)
+	if !strings.HasSuffix(f1.stk.Frames()[0].Function, "wsLevel1") {
+		t.Fatalf("skip=1: expected first frame wsLevel1; got %q", f1.stk.Frames()[0].Function)
+	}
+}
+
+func TestWithPinned_SurvivesRepeatedCtxBound(t *testing.T) {
+	t.Parallel()
+
+	starts := []Error{BadRequest("x"), Defect(errors.New("bug")), Interrupt("stop")}
+	for _, e := range starts {
+		// Interleave pinned and unpinned fields: u0 p1 u2 p3 u4.
+		e = e.With("u0", 0)
+		e = WithPinned(e, "p1", 1)
+		e = e.With("u2", 2)
+		e = WithPinned(e, "p3", 3)
+		e = e.With("u4", 4)
+
+		for i := 0; i < 4; i++ {
+			e = Wrap(e, "retry").CtxBound("", 4, "attempt", i)
+		}
+		var got []string
+		for k := range Fields(e) {
+			got = append(got, k)
+		}
+		// Unpinned fields go oldest first; pinned ones keep their place.
+		if strings.Join(got, ",") != "p1,p3,attempt,attempt" {
+			t.Fatalf("%T: fields after bounded wraps: %v", e, got)
+		}
+
+		// More pinned fields than the bound: every unpinned field goes,
+		// no pinned field does.
+		e = WithPinned(WithPinned(e, "p5", 5), "p6", 6).CtxBound("", 2)
+		got = got[:0]
+		for k := range Fields(e) {
+			got = append(got, k)
+		}
+		if strings.Join(got, ",") != "p1,p3,p5,p6" {
+			t.Fatalf("%T: over-pinned bound: %v", e, got)
+		}
+	}
+}
+
+// pinnedAt reports whether the i-th own field of e is pinned.
+func pinnedAt(e error, i int) bool {
+	p := Inspect(e).Pinned
+	return i < len(p) && p[i]
+}
+
+func TestWithPinned_NilForeignAndIsolation(t *testing.T) {
+	t.Parallel()
+
+	if e := WithPinned(nil, "request_id", "r1"); CodeOf(e) != CodeInternal || !pinnedAt(e, 0) {
+		t.Fatalf("nil: %+v", Inspect(e))
+	}
+	base := errors.New("io")
+	e := WithPinned(base, "request_id", "r1")
+	if !errors.Is(e, base) || !pinnedAt(e, 0) {
+		t.Fatalf("foreign: %+v", Inspect(e))
+	}
+
+	orig := BadRequest("x").With("a", 1)
+	_ = WithPinned(orig, "b", 2)
+	if n := Inspect(orig); len(n.Fields) != 1 || n.Pinned != nil {
+		t.Fatalf("original mutated: %+v", n)
+	}
+	pinned, plain := WithPinned(orig, "b", 2), orig.With("b", 2)
+	if !pinnedAt(pinned, 1) || pinnedAt(plain, 1) {
+		t.Fatalf("siblings must not share pin state")
+	}
+	for _, e := range []Error{Defect(errors.New("bug")), Interrupt("stop")} {
+		pe := WithPinned(e, "request_id", "r1")
+		if n := len(Inspect(pe).Fields); n == 0 || !pinnedAt(pe, n-1) {
+			t.Fatalf("%T: newest field not pinned: %+v", e, Inspect(pe))
+		}
+	}
+
+	// Field stays a plain, comparable key/value pair; pinning is node state.
+	if (Field{"k", 1}) != Inspect(WithPinned(nil, "k", 1)).Fields[0] {
+		t.Fatalf("pinned and unpinned fields should compare equal")
 	}
 }